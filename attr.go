@@ -1,9 +1,14 @@
 package attribs
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
 	"reflect"
+	"sort"
 	"strconv"
+	"strings"
 
 	"github.com/phonkee/attribs/parser"
 )
@@ -18,21 +23,37 @@ const (
 	attrTypeArray   attrType = "array"
 	attrTypeBoolean attrType = "boolean"
 	attrTypeMap     attrType = "map"
+	attrTypeNumber  attrType = "number"
+	attrTypeBig     attrType = "big" // big.Int, big.Float and big.Rat
 	attrTypeAny     attrType = "any" // any type is only supported in map, otherwise is impossible to get this type from inspect (since we pass values)
 )
 
 // inspect given value and return attribute
 func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
+	in := &inspector{cache: map[reflect.Type]*attr{}}
+	if len(c) > 0 && c[0] != nil {
+		in.cache = c[0]
+	}
+	return in.inspect(what)
+}
+
+// inspector inspects types into attributes, inspected structs are cached (support for recursion)
+type inspector struct {
+	cache map[reflect.Type]*attr
+
+	// nameSource is tag key used for names of fields without attr tag (Options.NameSource)
+	nameSource string
+}
+
+// inspect given value and return attribute
+func (in *inspector) inspect(what any) (*attr, error) {
 	if _, ok := what.(reflect.Type); ok {
 		panic("passing reflect.Type to inspect is not supported")
 	}
 	if _, ok := what.(reflect.Value); ok {
 		panic("passing reflect.Value to inspect is not supported")
 	}
-	cache := map[reflect.Type]*attr{}
-	if len(c) > 0 && c[0] != nil {
-		cache = c[0]
-	}
+	cache := in.cache
 
 	val := reflect.ValueOf(what)
 
@@ -50,16 +71,29 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 		val = val.Elem()
 	}
 
+	// Number has string kind and big numbers are structs, so they need to be checked before kinds
+	switch val.Type() {
+	case numberType:
+		result.Type = attrTypeNumber
+		return result, nil
+	case bigIntType, bigFloatType, bigRatType:
+		result.Type = attrTypeBig
+		return result, nil
+	}
+
 	switch val.Type().Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		result.Type = attrTypeInteger
 		result.Signed = true
+		result.Bits = val.Type().Bits()
 		break
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
 		result.Type = attrTypeInteger
+		result.Bits = val.Type().Bits()
 		break
 	case reflect.Float32, reflect.Float64:
 		result.Type = attrTypeFloat
+		result.Bits = val.Type().Bits()
 		break
 	case reflect.Bool:
 		result.Type = attrTypeBoolean
@@ -82,7 +116,7 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 			if newType.Kind() == reflect.Ptr && newType.IsNil() {
 				newType.Set(reflect.New(newType.Type().Elem()))
 			}
-			elem, err = inspect(newType.Interface(), cache)
+			elem, err = in.inspect(newType.Interface())
 			if err != nil {
 				return nil, err
 			}
@@ -113,10 +147,18 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 				continue
 			}
 
-			var (
-				err       error
-				fieldAttr *attr
-			)
+			// parse attribs tag first (or name from name source tag), disabled fields are not inspected
+			pa, err := fieldAttribs(fieldType, in.nameSource)
+			if err != nil {
+				return nil, err
+			}
+
+			// skip disabled fields
+			if pa.Disabled {
+				continue
+			}
+
+			var fieldAttr *attr
 
 			// prepare new value for field, so we can inspect it
 			if field.Kind() == reflect.Interface {
@@ -125,10 +167,10 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 				if field.Type().Kind() == reflect.Ptr {
 					// in case of pointer we get type what it points to and then reflect.New
 					// field attribute returned from inspect
-					fieldAttr, err = inspect(reflect.Indirect(reflect.New(field.Type().Elem())).Interface(), cache)
+					fieldAttr, err = in.inspect(reflect.Indirect(reflect.New(field.Type().Elem())).Interface())
 				} else {
 					// field attribute returned from inspect
-					fieldAttr, err = inspect(reflect.Indirect(reflect.New(field.Type())).Interface(), cache)
+					fieldAttr, err = in.inspect(reflect.Indirect(reflect.New(field.Type())).Interface())
 				}
 			}
 
@@ -137,20 +179,6 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 				return nil, err
 			}
 
-			// parse attribs tag first — only when the tag is actually present
-			pa := attrAttribs{Position: -1}
-			if tagStr, hasTag := fieldType.Tag.Lookup(TagName); hasTag {
-				pa, err = parseAttribsTag(tagStr, true)
-				if err != nil {
-					return nil, err
-				}
-			}
-
-			// skip disabled fields
-			if pa.Disabled {
-				continue
-			}
-
 			// Support for embedded structs
 			if fieldType.Anonymous {
 				fieldAttr.Name = fieldType.Type.Name()
@@ -166,6 +194,7 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 					// naive way
 					result.Properties[name] = prop
 				}
+				result.Order = append(result.Order, fieldAttr.Order...)
 			}
 
 			// names and aliases
@@ -179,9 +208,17 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 			// positional support
 			fieldAttr.Position = pa.Position
 			fieldAttr.IsPositional = pa.IsPositional
+			fieldAttr.Embedded = fieldType.Anonymous
+
+			// documentation
+			fieldAttr.Required = pa.Required
+			fieldAttr.Help = pa.Help
+			fieldAttr.Default = pa.Default
+			fieldAttr.Enum = pa.Enum
 
 			// add field attribute to struct properties
 			result.Properties[fieldAttr.Alias] = fieldAttr
+			result.Order = append(result.Order, fieldAttr.Alias)
 		}
 	case reflect.Map:
 		result.Type = attrTypeMap
@@ -214,7 +251,7 @@ func inspect(what any, c ...map[reflect.Type]*attr) (*attr, error) {
 			}()
 
 			// field attribute returned from inspect
-			elemAttr, err = inspect(newValue, cache)
+			elemAttr, err = in.inspect(newValue)
 			if err != nil {
 				return nil, err
 			}
@@ -240,17 +277,31 @@ type attr struct {
 	// integer type
 	Signed bool
 
+	// exact bit size of integer and float types (int8 => 8)
+	Bits int
+
 	// array/slice/map (for map we don't need key since only string is supported)
 	// we also use elem for already parsed attributes (recursion)
 	Elem *attr
 
-	// struct properties
+	// struct properties and their names in declaration order (promoted fields of embedded structs precede the
+	// embedded struct itself)
 	Properties map[string]*attr
+	Order      []string
 
 	// Positional argument support: Position >= 0 when the field accepts a positional arg.
 	Position     int
 	IsPositional bool
 
+	// Embedded struct field, its properties are promoted to parent struct
+	Embedded bool
+
+	// documentation from tag (required, help, default and enum), it's not enforced and it's used by Usage
+	Required bool
+	Help     string
+	Default  string
+	Enum     []string
+
 	// Parent for better debugging
 	Parent *attr
 }
@@ -262,35 +313,58 @@ func anyAttr() *attr {
 	}
 }
 
+// setOptions configure how Set sets values
+type setOptions struct {
+	// ignoreUnknown skips unknown attributes and positional arguments instead of failing
+	ignoreUnknown bool
+
+	// useNumber sets numbers to any values as Number instead of int or float64
+	useNumber bool
+}
+
 // Set sets value to given target from parser.
 // it returns error if value cannot be set or parsed attribute is invalid
-func (a *attr) Set(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+func (a *attr) Set(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
+	// null clears the value, so it needs to be handled before we allocate pointer
+	if parsed.Value != nil && parsed.Value.Null {
+		return a.setNull(target, parsed)
+	}
+
 	// check if pointer is not nil, we need to provide new value
 	if target.Kind() == reflect.Ptr && target.IsNil() {
 		target.Set(reflect.New(target.Type().Elem()))
 	}
 
+	// setters work with values, not pointers
+	if target.Kind() == reflect.Ptr {
+		target = target.Elem()
+	}
+
 	switch a.Type {
 	case attrTypeArray:
-		return a.setArray(target, parsed, ignoreUnknown)
+		return a.setArray(target, parsed, options)
 	case attrTypeBoolean:
-		return a.setBoolean(target, parsed, ignoreUnknown)
+		return a.setBoolean(target, parsed, options)
 	case attrTypeFloat:
-		return a.setFloat(target, parsed, ignoreUnknown)
+		return a.setFloat(target, parsed, options)
 	case attrTypeInteger:
-		return a.setInteger(target, parsed, ignoreUnknown)
+		return a.setInteger(target, parsed, options)
 	case attrTypeString:
-		return a.setString(target, parsed, ignoreUnknown)
+		return a.setString(target, parsed, options)
 	case attrTypeStruct:
-		return a.setStruct(target, parsed, ignoreUnknown)
+		return a.setStruct(target, parsed, options)
 	case attrTypeMap:
-		return a.setMap(target, parsed, ignoreUnknown)
+		return a.setMap(target, parsed, options)
+	case attrTypeNumber:
+		return a.setNumber(target, parsed, options)
+	case attrTypeBig:
+		return a.setBig(target, parsed, options)
 	default:
 		return parser.NewParseError(parsed.Span, "invalid attribute type %v", a.Type)
 	}
 }
 
-func (a *attr) setArray(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+func (a *attr) setArray(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	if parsed.Array == nil {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 	}
@@ -311,15 +385,15 @@ func (a *attr) setArray(target reflect.Value, parsed *parser.Attribute, ignoreUn
 			val reflect.Value
 		)
 		if a.Elem.Type == attrTypeAny {
-			val, err = item.Build()
+			val, err = buildAny(item, options)
 			if err != nil {
 				return err
 			}
 		} else {
 			val = reflect.Indirect(reflect.New(target.Type().Elem()))
 
-			if err := a.Elem.Set(val, item, ignoreUnknown); err != nil {
-				return fmt.Errorf("cannot set array value for %s: %s", parsed.Name, err)
+			if err := a.Elem.Set(val, item, options); err != nil {
+				return fmt.Errorf("cannot set array value for %s: %w", parsed.Name, err)
 			}
 		}
 		nu = reflect.Append(nu, val)
@@ -330,7 +404,20 @@ func (a *attr) setArray(target reflect.Value, parsed *parser.Attribute, ignoreUn
 	return nil
 }
 
-func (a *attr) setBoolean(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+// setNull sets explicit nil to pointer, slice, map and interface targets, other types are not nullable.
+func (a *attr) setNull(target reflect.Value, parsed *parser.Attribute) error {
+	switch target.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Map, reflect.Interface:
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	if parsed.Name == "" {
+		return parser.NewParseError(parsed.Value.Span, "cannot set null: %s is not nullable", target.Type())
+	}
+	return parser.NewParseError(parsed.Value.Span, "cannot set null to %s: %s is not nullable", parsed.Name, target.Type())
+}
+
+func (a *attr) setBoolean(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	if parsed.Value == nil || parsed.Value.Boolean == nil {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 	}
@@ -349,20 +436,23 @@ func (a *attr) setBoolean(target reflect.Value, parsed *parser.Attribute, ignore
 	return nil
 }
 
-func (a *attr) setFloat(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+func (a *attr) setFloat(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	if parsed.Value == nil || parsed.Value.Number == nil {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 	}
-	if value, err := strconv.ParseFloat(*parsed.Value.Number, 64); err != nil {
+	value, err := parser.ParseFloat(*parsed.Value.Number, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
-	} else {
-		target.SetFloat(value)
 	}
+	if err != nil || target.OverflowFloat(value) {
+		return a.rangeError(target, parsed.Value)
+	}
+	target.SetFloat(value)
 	return nil
 
 }
 
-func (a *attr) setInteger(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+func (a *attr) setInteger(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	if parsed.Value == nil || parsed.Value.Number == nil {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 	}
@@ -371,23 +461,58 @@ func (a *attr) setInteger(target reflect.Value, parsed *parser.Attribute, ignore
 		target = target.Elem()
 	}
 
+	// parse as 64 bit and check overflow for the target, so we don't silently truncate values (e.g. 300 into int8)
+	literal := *parsed.Value.Number
 	if a.Signed {
-		val, err := strconv.ParseInt(*parsed.Value.Number, 10, 64)
-		if err != nil {
+		val, err := parser.ParseInt(literal, 64)
+		if err != nil && !errors.Is(err, strconv.ErrRange) {
 			return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 		}
+		if err != nil || target.OverflowInt(val) {
+			return a.rangeError(target, parsed.Value)
+		}
 		target.SetInt(val)
 	} else {
-		val, err := strconv.ParseUint(*parsed.Value.Number, 10, 64)
+		val, err := parser.ParseUint(literal, 64)
 		if err != nil {
+			// negative number is valid literal, it's just out of range for unsigned integer
+			if _, signedErr := parser.ParseInt(literal, 64); errors.Is(err, strconv.ErrRange) || signedErr == nil || errors.Is(signedErr, strconv.ErrRange) {
+				return a.rangeError(target, parsed.Value)
+			}
 			return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 		}
+		if target.OverflowUint(val) {
+			return a.rangeError(target, parsed.Value)
+		}
 		target.SetUint(val)
 	}
 	return nil
 }
 
-func (a *attr) setMap(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+// rangeError returns error for number value that doesn't fit target, it names Go type and its range.
+func (a *attr) rangeError(target reflect.Value, value *parser.Value) error {
+	typeName := target.Type().String()
+	if kind := target.Kind().String(); kind != typeName {
+		typeName = fmt.Sprintf("%s (%s)", typeName, kind)
+	}
+
+	var valueRange string
+	switch {
+	case a.Type == attrTypeFloat && a.Bits == 32:
+		valueRange = fmt.Sprintf("±%g", math.MaxFloat32)
+	case a.Type == attrTypeFloat:
+		valueRange = fmt.Sprintf("±%g", math.MaxFloat64)
+	case a.Signed:
+		minimum := int64(-1) << (a.Bits - 1)
+		valueRange = fmt.Sprintf("%d..%d", minimum, -(minimum + 1))
+	default:
+		valueRange = fmt.Sprintf("0..%d", ^uint64(0)>>(64-a.Bits))
+	}
+
+	return parser.NewParseError(value.Span, "value %s out of range for %s: %s", *value.Number, typeName, valueRange)
+}
+
+func (a *attr) setMap(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	// check if we really have object type, otherwise it's invalid
 	if parsed.Object == nil {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
@@ -402,20 +527,33 @@ func (a *attr) setMap(target reflect.Value, parsed *parser.Attribute, ignoreUnkn
 	switch a.Elem.Type {
 	case attrTypeAny:
 		// special case for any type, we need to build recursively maps and stuff
-		v, err := parsed.Build()
+		v, err := buildAny(parsed, options)
 		if err != nil {
 			return err
 		}
 		target.Set(v)
 	default:
-		// TODO: non any elem type
-
+		attrs, err := parsed.Object.ExpandPaths()
+		if err != nil {
+			return err
+		}
+		for _, att := range attrs.Attributes {
+			// positional values have no key (quoted empty string is valid key though)
+			if att.Name == "" && !att.Quoted {
+				return parser.NewParseError(att.Span, "expected key for map %s", parsed.Name)
+			}
+			val := reflect.New(target.Type().Elem()).Elem()
+			if err := a.Elem.Set(val, att, options); err != nil {
+				return fmt.Errorf("cannot set map value for %s: %w", parsed.Name, err)
+			}
+			target.SetMapIndex(reflect.ValueOf(att.Name), val)
+		}
 	}
 
 	return nil
 }
 
-func (a *attr) setString(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+func (a *attr) setString(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	if parsed.Value == nil || parsed.Value.String == nil {
 		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
 	}
@@ -428,21 +566,75 @@ func (a *attr) setString(target reflect.Value, parsed *parser.Attribute, ignoreU
 	return nil
 }
 
-func (a *attr) setStruct(target reflect.Value, parsed *parser.Attribute, ignoreUnknown bool) error {
+// setNumber sets number literal as it was written in input
+func (a *attr) setNumber(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
+	if parsed.Value == nil || parsed.Value.Number == nil {
+		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
+	}
+
+	target.SetString(*parsed.Value.Number)
+
+	return nil
+}
+
+// setBig sets big.Int, big.Float or big.Rat, integers have no range and decimals are exact for big.Rat
+func (a *attr) setBig(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
+	if parsed.Value == nil || parsed.Value.Number == nil {
+		return parser.NewParseError(parsed.Span, "invalid value for %s", parsed.Name)
+	}
+
+	var err error
+	literal := *parsed.Value.Number
+	switch value := target.Addr().Interface().(type) {
+	case *big.Int:
+		var parsedValue *big.Int
+		if parsedValue, err = parser.ParseBigInt(literal); err == nil {
+			value.Set(parsedValue)
+		}
+	case *big.Float:
+		var parsedValue *big.Float
+		if parsedValue, err = parser.ParseBigFloat(literal, value.Prec()); err == nil {
+			value.Set(parsedValue)
+		}
+	case *big.Rat:
+		var parsedValue *big.Rat
+		if parsedValue, err = parser.ParseBigRat(literal); err == nil {
+			value.Set(parsedValue)
+		}
+	}
+	if err != nil {
+		return parser.NewParseError(parsed.Value.Span, "invalid value %s for %s: %s", literal, parsed.Name, target.Type())
+	}
+
+	return nil
+}
+
+func (a *attr) setStruct(target reflect.Value, parsed *parser.Attribute, options setOptions) error {
 	// if this was recursive call, we need to check Elem
 	if a.Elem != nil {
-		return a.Elem.setStruct(target, parsed, ignoreUnknown)
+		return a.Elem.setStruct(target, parsed, options)
 	}
 
 	if parsed.Object == nil {
-		if ignoreUnknown {
+		if options.ignoreUnknown {
 			return nil
 		}
 		return parser.NewParseError(parsed.Span, "expected object for struct field %s", parsed.Name)
 	}
 
+	// dotted paths (db.pool.max=10) are resolved level by level into nested objects
+	attrs, err := parsed.Object.ExpandPaths()
+	if err != nil {
+		return err
+	}
+
 	positionalIndex := 0
-	for _, att := range parsed.Object.Attributes {
+	for _, att := range attrs.Attributes {
+		// quoted keys are only for maps, struct fields are always identifiers
+		if att.Quoted {
+			return parser.NewParseError(att.Span, "quoted key %q is not allowed in struct %s", att.Name, parsed.Name)
+		}
+
 		var prop *attr
 		if att.Name == "" {
 			// Positional argument: find the field declared with pos=positionalIndex.
@@ -453,7 +645,7 @@ func (a *attr) setStruct(target reflect.Value, parsed *parser.Attribute, ignoreU
 				}
 			}
 			if prop == nil {
-				if ignoreUnknown {
+				if options.ignoreUnknown {
 					positionalIndex++
 					continue
 				}
@@ -464,10 +656,10 @@ func (a *attr) setStruct(target reflect.Value, parsed *parser.Attribute, ignoreU
 			var ok bool
 			prop, ok = a.Properties[att.Name]
 			if !ok {
-				if ignoreUnknown {
+				if options.ignoreUnknown {
 					continue
 				}
-				return parser.NewParseError(att.Span, "unknown attribute %s", att.Name)
+				return parser.NewUnknownAttributeError(att.Span, att.Name, a.suggest(att.Name))
 			}
 		}
 
@@ -479,17 +671,40 @@ func (a *attr) setStruct(target reflect.Value, parsed *parser.Attribute, ignoreU
 		}
 		// if any type, we just build reflect.Value and set it
 		if field.Kind() == reflect.Interface {
-			v, err := att.Build()
+			v, err := buildAny(att, options)
 			if err != nil {
 				return err
 			}
 			field.Set(v)
 		} else {
 			// set property
-			if err := prop.Set(field, att, ignoreUnknown); err != nil {
+			if err := prop.Set(field, att, options); err != nil {
 				return err
 			}
 		}
 	}
 	return nil
 }
+
+// suggest returns attribute name closest to unknown name. Attribute names that differ only in case win, then Go
+// field names suggest attribute names of their fields (Label suggests title for field Label with name=title).
+// Candidates are checked in sorted order, so the suggestion is the same on every run.
+func (a *attr) suggest(name string) string {
+	candidates := make([]string, 0, len(a.Properties))
+	for alias := range a.Properties {
+		candidates = append(candidates, alias)
+	}
+	sort.Strings(candidates)
+
+	for _, alias := range candidates {
+		if strings.EqualFold(alias, name) {
+			return alias
+		}
+	}
+	for _, alias := range candidates {
+		if strings.EqualFold(a.Properties[alias].Name, name) {
+			return alias
+		}
+	}
+	return parser.Suggest(name, candidates)
+}
@@ -3,6 +3,7 @@ package attribs
 import (
 	"github.com/stretchr/testify/assert"
 	"reflect"
+	"strconv"
 	"testing"
 )
 
@@ -18,27 +19,28 @@ func TestInspect(t *testing.T) {
 				input    interface{}
 				nullable bool
 				signed   bool
+				bits     int
 			}{
-				{int(1), false, true},
-				{int8(1), false, true},
-				{int16(1), false, true},
-				{int32(1), false, true},
-				{int64(1), false, true},
-				{ptrTo(int(1)), true, true},
-				{ptrTo(int8(1)), true, true},
-				{ptrTo(int16(1)), true, true},
-				{ptrTo(int32(1)), true, true},
-				{ptrTo(int64(1)), true, true},
-				{uint(1), false, false},
-				{uint8(1), false, false},
-				{uint16(1), false, false},
-				{uint32(1), false, false},
-				{uint64(1), false, false},
-				{ptrTo(uint(1)), true, false},
-				{ptrTo(uint8(1)), true, false},
-				{ptrTo(uint16(1)), true, false},
-				{ptrTo(uint32(1)), true, false},
-				{ptrTo(uint64(1)), true, false},
+				{int(1), false, true, strconv.IntSize},
+				{int8(1), false, true, 8},
+				{int16(1), false, true, 16},
+				{int32(1), false, true, 32},
+				{int64(1), false, true, 64},
+				{ptrTo(int(1)), true, true, strconv.IntSize},
+				{ptrTo(int8(1)), true, true, 8},
+				{ptrTo(int16(1)), true, true, 16},
+				{ptrTo(int32(1)), true, true, 32},
+				{ptrTo(int64(1)), true, true, 64},
+				{uint(1), false, false, strconv.IntSize},
+				{uint8(1), false, false, 8},
+				{uint16(1), false, false, 16},
+				{uint32(1), false, false, 32},
+				{uint64(1), false, false, 64},
+				{ptrTo(uint(1)), true, false, strconv.IntSize},
+				{ptrTo(uint8(1)), true, false, 8},
+				{ptrTo(uint16(1)), true, false, 16},
+				{ptrTo(uint32(1)), true, false, 32},
+				{ptrTo(uint64(1)), true, false, 64},
 			}
 
 			for _, item := range data {
@@ -47,10 +49,28 @@ func TestInspect(t *testing.T) {
 				assert.NotNil(t, a)
 				assert.Equal(t, attrTypeInteger, a.Type)
 				assert.Equal(t, item.nullable, a.Nullable)
+				assert.Equal(t, item.signed, a.Signed)
+				assert.Equal(t, item.bits, a.Bits)
 			}
 		})
 	})
 
+	t.Run("test float", func(t *testing.T) {
+		for _, item := range []struct {
+			input any
+			bits  int
+		}{
+			{float32(1), 32},
+			{float64(1), 64},
+			{ptrTo(float32(1)), 32},
+		} {
+			a, err := inspect(item.input, map[reflect.Type]*attr{})
+			assert.NoError(t, err)
+			assert.Equal(t, attrTypeFloat, a.Type)
+			assert.Equal(t, item.bits, a.Bits)
+		}
+	})
+
 	type TestStruct struct {
 		ID          string  `attr:"name=id"`
 		Description *string `attr:"name=description"`
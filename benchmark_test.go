@@ -0,0 +1,96 @@
+package attribs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phonkee/attribs"
+)
+
+type benchSpan struct {
+	Start int `attr:"name=start"`
+	End   int `attr:"name=end"`
+}
+
+type benchTag struct {
+	Name     string     `attr:"name=name"`
+	Required bool       `attr:"name=required"`
+	Span     *benchSpan `attr:"name=span"`
+	Tags     []string   `attr:"name=tags"`
+}
+
+const benchTagInput = "name='user_id', required, span(start=0, end=255), tags['id', 'primary']"
+
+// benchRecursiveInput returns RecursiveStruct input nested depth levels deep.
+func benchRecursiveInput(depth int) string {
+	return strings.Repeat("inner(struct(", depth) + "inner(hello='world')" + strings.Repeat("))", depth)
+}
+
+func benchmarkDefinitionParse[T any](b *testing.B, def attribs.Definition[T], input string) {
+	b.Helper()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for b.Loop() {
+		if _, err := def.Parse(input, false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("tag", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := attribs.New(benchTag{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("recursive", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if _, err := attribs.New(RecursiveStruct{}); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkDefinitionParse(b *testing.B) {
+	tagDef := attribs.Must(attribs.New(benchTag{}))
+	recursiveDef := attribs.Must(attribs.New(RecursiveStruct{}))
+
+	b.Run("small_tag", func(b *testing.B) { benchmarkDefinitionParse(b, tagDef, benchTagInput) })
+	b.Run("recursive_4", func(b *testing.B) { benchmarkDefinitionParse(b, recursiveDef, benchRecursiveInput(4)) })
+	b.Run("recursive_16", func(b *testing.B) { benchmarkDefinitionParse(b, recursiveDef, benchRecursiveInput(16)) })
+}
+
+// TestDefinitionParseAllocs locks in allocation ceilings for Definition.Parse.
+// Ceilings are about 8% above the measured values (small_tag 530, recursive_4 813); lower them when an
+// optimization lands.
+func TestDefinitionParseAllocs(t *testing.T) {
+	tagDef := attribs.Must(attribs.New(benchTag{}))
+	recursiveDef := attribs.Must(attribs.New(RecursiveStruct{}))
+
+	t.Run("small_tag", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(20, func() {
+			if _, err := tagDef.Parse(benchTagInput, false); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs > 570 {
+			t.Errorf("Parse allocated %.0f times, budget is 570", allocs)
+		}
+	})
+
+	t.Run("recursive_4", func(t *testing.T) {
+		allocs := testing.AllocsPerRun(20, func() {
+			if _, err := recursiveDef.Parse(benchRecursiveInput(4), false); err != nil {
+				t.Fatal(err)
+			}
+		})
+		if allocs > 875 {
+			t.Errorf("Parse allocated %.0f times, budget is 875", allocs)
+		}
+	})
+}
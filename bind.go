@@ -0,0 +1,323 @@
+package attribs
+
+import (
+	"flag"
+	"math/big"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+// BindOptions are sources of Definition.Bind
+type BindOptions struct {
+	// EnvPrefix is prefix of environment variables (APP gives APP_SPAN_START for span.start), empty means no prefix
+	EnvPrefix string
+
+	// Environ returns environment as key=value pairs, nil means os.Environ. Tests can pass fixed environment.
+	Environ func() []string
+
+	// Flags is parsed flag set, flags named by attribute paths (span.start) are used when they were set on command
+	// line. Definition.RegisterFlags registers them, but flags registered by hand with the same names work too.
+	Flags *flag.FlagSet
+
+	// Input is attribute string with the highest precedence, empty input is skipped
+	Input string
+
+	// IgnoreUnknown ignores unknown attributes in Input
+	IgnoreUnknown bool
+}
+
+// bindField is field (or field of nested struct) that can be bound to environment variable and flag
+type bindField struct {
+	path []string
+	attr *attr
+}
+
+// flagName returns name of command-line flag of field (span.start)
+func (b bindField) flagName() string {
+	return strings.Join(b.path, ".")
+}
+
+// envName returns name of environment variable of field (APP_SPAN_START)
+func (b bindField) envName(prefix string) string {
+	name := strings.ToUpper(strings.Join(b.path, "_"))
+	if prefix != "" {
+		name = prefix + "_" + name
+	}
+	return name
+}
+
+// attribute returns attribute setting field from root struct to text value. Scalars take text as it is, arrays,
+// maps, structs and any values written as positional object or array ([a, b] or (min=1)) are parsed with options.
+func (b bindField) attribute(text string, options parser.Options) (*parser.Attribute, error) {
+	span := &parser.SourceSpan{}
+	result := &parser.Attribute{Name: b.flagName(), Span: span, Value: parser.TextValue(text)}
+	if len(b.path) > 1 {
+		result.Path = b.path
+	}
+
+	switch b.attr.Type {
+	case attrTypeArray, attrTypeMap, attrTypeStruct, attrTypeAny:
+		trimmed := strings.TrimSpace(text)
+		if !strings.HasPrefix(trimmed, "(") && !strings.HasPrefix(trimmed, "[") {
+			switch b.attr.Type {
+			case attrTypeArray:
+				return nil, parser.NewParseError(span, "invalid value for %s: expected positional array [...]", b.flagName())
+			case attrTypeMap, attrTypeStruct:
+				return nil, parser.NewParseError(span, "invalid value for %s: expected positional object (...)", b.flagName())
+			}
+			break
+		}
+		parsed, err := parser.Parse(strings.NewReader(trimmed), options)
+		if err != nil {
+			return nil, err
+		}
+		if len(parsed.Object.Attributes) != 1 {
+			return nil, parser.NewParseError(parsed.Span, "expected single object or array")
+		}
+		value := parsed.Object.Attributes[0]
+		result.Value, result.Object, result.Array = nil, value.Object, value.Array
+	}
+
+	root := &parser.Attribute{Span: span, Object: &parser.Attributes{Span: span}}
+	root.Object.Push(result)
+	return root, nil
+}
+
+// bindFields returns fields of struct attribute sorted by path, fields of nested structs are listed instead of the
+// struct itself and fields of embedded structs are promoted. Recursive structs are not expanded.
+func bindFields(a *attr) []bindField {
+	var (
+		result []bindField
+		walk   func(a *attr, path []string, visiting map[*attr]bool)
+	)
+	walk = func(a *attr, path []string, visiting map[*attr]bool) {
+		for a.Elem != nil && a.Type == attrTypeStruct {
+			a = a.Elem
+		}
+		visiting[a] = true
+		defer delete(visiting, a)
+
+		for name, prop := range a.Properties {
+			if prop.Embedded {
+				continue
+			}
+			propPath := append(append([]string{}, path...), name)
+
+			nested := prop
+			for nested.Elem != nil && nested.Type == attrTypeStruct {
+				nested = nested.Elem
+			}
+			if nested.Type == attrTypeStruct && !visiting[nested] {
+				walk(nested, propPath, visiting)
+				continue
+			}
+			result = append(result, bindField{path: propPath, attr: prop})
+		}
+	}
+	walk(a, nil, map[*attr]bool{})
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].flagName() < result[j].flagName()
+	})
+	return result
+}
+
+// bindError is error of value from environment variable or flag. Values set as they are have no location, so their
+// errors are reported without span.
+type bindError struct {
+	source string
+	err    error
+}
+
+func (b bindError) Error() string {
+	if pe, ok := b.err.(parser.ParseError); ok && pe.Line() == 0 {
+		return b.source + ": " + pe.Message()
+	}
+	return b.source + ": " + b.err.Error()
+}
+
+func (b bindError) Unwrap() error {
+	return b.err
+}
+
+// bindFlag is flag.Value of registered field, values are converted when Bind is called
+type bindFlag struct {
+	value  string
+	isBool bool
+}
+
+func (f *bindFlag) String() string {
+	if f == nil {
+		return ""
+	}
+	return f.value
+}
+
+func (f *bindFlag) Set(value string) error {
+	f.value = value
+	return nil
+}
+
+// IsBoolFlag makes boolean fields bare flags (-verbose)
+func (f *bindFlag) IsBoolFlag() bool {
+	return f.isBool
+}
+
+// RegisterFlags registers flag for every field of definition named by its attribute path (-span.start), boolean
+// fields are bare flags. Usage of flag is help of attribute or its type, same as in Usage. Flags are read by Bind.
+func (d Definition[T]) RegisterFlags(fs *flag.FlagSet) {
+	for _, field := range bindFields(d.attr) {
+		usage := field.attr.Help
+		if usage == "" {
+			usage = usageType(field.attr)
+		}
+		fs.Var(&bindFlag{isBool: field.attr.Type == attrTypeBoolean}, field.flagName(), usage)
+	}
+}
+
+// Bind returns value filled from sources in order of precedence: defaults, environment variables, flags and
+// Input, so every source overrides fields set by the previous ones. Environment variable of field is its path
+// joined by "_" in upper case (APP_SPAN_START) and flag is its path (-span.start). Values are converted the same
+// way as values in attribute string (arrays, maps and structs are written as positional array or object, [a, b] or
+// (min=1)), errors name environment variable or flag. Defaults are copied deeply, so they are never changed.
+func (d Definition[T]) Bind(defaults T, options BindOptions) (T, error) {
+	result := reflect.New(reflect.TypeOf(*new(T))).Elem()
+	result.Set(deepCopy(reflect.ValueOf(defaults), map[copiedPointer]reflect.Value{}))
+
+	setOpts := setOptions{useNumber: d.options.UseNumber}
+	set := func(field bindField, text, source string) error {
+		root, err := field.attribute(text, d.options.Parser)
+		if err == nil {
+			err = d.attr.Set(result, root, setOpts)
+		}
+		if err != nil {
+			return bindError{source: source, err: err}
+		}
+		return nil
+	}
+
+	fields := bindFields(d.attr)
+
+	environ := options.Environ
+	if environ == nil {
+		environ = os.Environ
+	}
+	env := make(map[string]string)
+	for _, item := range environ() {
+		if key, value, ok := strings.Cut(item, "="); ok {
+			env[key] = value
+		}
+	}
+	for _, field := range fields {
+		name := field.envName(options.EnvPrefix)
+		if value, ok := env[name]; ok {
+			if err := set(field, value, "environment variable "+name); err != nil {
+				return result.Interface().(T), err
+			}
+		}
+	}
+
+	if options.Flags != nil {
+		byName := make(map[string]bindField, len(fields))
+		for _, field := range fields {
+			byName[field.flagName()] = field
+		}
+		var err error
+		options.Flags.Visit(func(f *flag.Flag) {
+			if field, ok := byName[f.Name]; ok && err == nil {
+				err = set(field, f.Value.String(), "flag -"+f.Name)
+			}
+		})
+		if err != nil {
+			return result.Interface().(T), err
+		}
+	}
+
+	if options.Input != "" {
+		parsed, err := parser.Parse(strings.NewReader(options.Input), d.options.Parser)
+		if err != nil {
+			return result.Interface().(T), err
+		}
+		setOpts.ignoreUnknown = options.IgnoreUnknown
+		if err := d.attr.Set(result, parsed, setOpts); err != nil {
+			return result.Interface().(T), err
+		}
+	}
+
+	return result.Interface().(T), nil
+}
+
+// copiedPointer identifies pointer copied by deepCopy, so shared and cyclic pointers are copied once
+type copiedPointer struct {
+	typ     reflect.Type
+	address uintptr
+}
+
+// deepCopy returns copy of value with copies of values behind pointers, maps, slices and interfaces. Unexported
+// fields are copied as they are, big numbers are copied by their Set method.
+func deepCopy(value reflect.Value, copied map[copiedPointer]reflect.Value) reflect.Value {
+	result := reflect.New(value.Type()).Elem()
+	switch value.Kind() {
+	case reflect.Pointer:
+		if value.IsNil() {
+			break
+		}
+		key := copiedPointer{typ: value.Type(), address: value.Pointer()}
+		if existing, ok := copied[key]; ok {
+			return existing
+		}
+		result.Set(reflect.New(value.Type().Elem()))
+		copied[key] = result
+		result.Elem().Set(deepCopy(value.Elem(), copied))
+	case reflect.Map:
+		if value.IsNil() {
+			break
+		}
+		result.Set(reflect.MakeMapWithSize(value.Type(), value.Len()))
+		for iter := value.MapRange(); iter.Next(); {
+			result.SetMapIndex(iter.Key(), deepCopy(iter.Value(), copied))
+		}
+	case reflect.Slice:
+		if value.IsNil() {
+			break
+		}
+		result.Set(reflect.MakeSlice(value.Type(), value.Len(), value.Len()))
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(deepCopy(value.Index(i), copied))
+		}
+	case reflect.Array:
+		for i := 0; i < value.Len(); i++ {
+			result.Index(i).Set(deepCopy(value.Index(i), copied))
+		}
+	case reflect.Interface:
+		if !value.IsNil() {
+			result.Set(deepCopy(value.Elem(), copied))
+		}
+	case reflect.Struct:
+		// big numbers keep their digits in unexported slices
+		switch original := value.Interface().(type) {
+		case big.Int:
+			result.Addr().Interface().(*big.Int).Set(&original)
+			return result
+		case big.Float:
+			result.Addr().Interface().(*big.Float).Set(&original)
+			return result
+		case big.Rat:
+			result.Addr().Interface().(*big.Rat).Set(&original)
+			return result
+		}
+		result.Set(value)
+		for i := 0; i < value.NumField(); i++ {
+			if field := result.Field(i); field.CanSet() {
+				field.Set(deepCopy(value.Field(i), copied))
+			}
+		}
+	default:
+		result.Set(value)
+	}
+	return result
+}
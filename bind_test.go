@@ -0,0 +1,207 @@
+package attribs_test
+
+import (
+	"flag"
+	"io"
+	"math/big"
+	"testing"
+
+	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/parser"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBind(t *testing.T) {
+	type Span struct {
+		Start int `attr:"name=start"`
+		End   int `attr:"name=end"`
+	}
+	type Base struct {
+		Debug bool `attr:"name=debug"`
+	}
+	type Node struct {
+		Name     string `attr:"name=name"`
+		Children []Node `attr:"name=children"`
+	}
+	type Component struct {
+		Base
+		Name    string         `attr:"name=name"`
+		Verbose bool           `attr:"name=verbose"`
+		Span    Span           `attr:"name=span"`
+		Limit   *Span          `attr:"name=limit"`
+		Tags    []string       `attr:"name=tags"`
+		Extra   map[string]any `attr:"name=extra"`
+		Node    Node           `attr:"name=node"`
+	}
+
+	d := attribs.Must(attribs.New(Component{}))
+	environ := func(env ...string) func() []string {
+		return func() []string { return env }
+	}
+	flags := func(args ...string) *flag.FlagSet {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.SetOutput(io.Discard)
+		d.RegisterFlags(fs)
+		assert.NoError(t, fs.Parse(args))
+		return fs
+	}
+
+	t.Run("test precedence", func(t *testing.T) {
+		defaults := Component{Name: "default", Span: Span{Start: 1, End: 2}}
+		value, err := d.Bind(defaults, attribs.BindOptions{
+			EnvPrefix: "APP",
+			Environ:   environ("APP_NAME=env", "APP_SPAN_START=3", "APP_SPAN_END=4", "APP_DEBUG=true", "OTHER=x", "APP_TAGS=[a, b]"),
+			Flags:     flags("-span.start=5", "-verbose", "-limit.end", "0x10"),
+			Input:     "span.end=6",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, Component{
+			Base:    Base{Debug: true},
+			Name:    "env",
+			Verbose: true,
+			Span:    Span{Start: 5, End: 6},
+			Limit:   &Span{End: 16},
+			Tags:    []string{"a", "b"},
+		}, value)
+
+		// defaults are not changed
+		assert.Equal(t, Component{Name: "default", Span: Span{Start: 1, End: 2}}, defaults)
+	})
+
+	t.Run("test defaults are copied", func(t *testing.T) {
+		defaults := Component{
+			Limit: &Span{Start: 1},
+			Tags:  []string{"a"},
+			Extra: map[string]any{"a": map[string]any{"b": 1}},
+			Node:  Node{Children: []Node{{Name: "leaf"}}},
+		}
+		value, err := d.Bind(defaults, attribs.BindOptions{
+			Environ: environ("LIMIT_END=5", "EXTRA=(c=2)", "NODE_CHILDREN=[(name=other)]"),
+			Input:   "tags[b]",
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, &Span{Start: 1, End: 5}, value.Limit)
+		assert.Equal(t, map[string]any{"c": 2}, value.Extra)
+
+		// defaults are not changed
+		assert.Equal(t, Component{
+			Limit: &Span{Start: 1},
+			Tags:  []string{"a"},
+			Extra: map[string]any{"a": map[string]any{"b": 1}},
+			Node:  Node{Children: []Node{{Name: "leaf"}}},
+		}, defaults)
+
+		type Numbers struct {
+			Count big.Int  `attr:"name=count"`
+			Ratio *big.Rat `attr:"name=ratio"`
+		}
+		numbers := Numbers{Ratio: big.NewRat(1, 3)}
+		numbers.Count.SetInt64(1 << 62)
+		bound, err := attribs.Must(attribs.New(Numbers{})).Bind(numbers, attribs.BindOptions{Environ: environ("COUNT=7", "RATIO=0.5")})
+		assert.NoError(t, err)
+		assert.Equal(t, "7 1/2", bound.Count.String()+" "+bound.Ratio.String())
+		assert.Equal(t, "4611686018427387904 1/3", numbers.Count.String()+" "+numbers.Ratio.String())
+	})
+
+	t.Run("test parser options", func(t *testing.T) {
+		td := attribs.Must(attribs.New(Component{}, attribs.Options{Parser: parser.Options{TrailingCommas: true}}))
+		value, err := td.Bind(Component{}, attribs.BindOptions{EnvPrefix: "APP", Environ: environ("APP_TAGS=[a, b,]")})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"a", "b"}, value.Tags)
+
+		_, err = d.Bind(Component{}, attribs.BindOptions{EnvPrefix: "APP", Environ: environ("APP_TAGS=[a, b,]")})
+		assert.Error(t, err)
+	})
+
+	t.Run("test defaults only", func(t *testing.T) {
+		value, err := d.Bind(Component{Name: "default"}, attribs.BindOptions{Environ: environ()})
+		assert.NoError(t, err)
+		assert.Equal(t, Component{Name: "default"}, value)
+	})
+
+	t.Run("test complex values", func(t *testing.T) {
+		value, err := d.Bind(Component{}, attribs.BindOptions{
+			Environ: environ("EXTRA=(a=1, b[x])", "NODE_CHILDREN=[(name=leaf)]", "NODE_NAME=root"),
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]any{"a": 1, "b": []any{"x"}}, value.Extra)
+		assert.Equal(t, Node{Name: "root", Children: []Node{{Name: "leaf"}}}, value.Node)
+	})
+
+	t.Run("test pointer", func(t *testing.T) {
+		pd := attribs.Must(attribs.New(&Span{}))
+		defaults := &Span{Start: 1}
+		value, err := pd.Bind(defaults, attribs.BindOptions{Environ: environ("END=2")})
+		assert.NoError(t, err)
+		assert.Equal(t, &Span{Start: 1, End: 2}, value)
+		assert.Equal(t, &Span{Start: 1}, defaults)
+
+		value, err = pd.Bind(nil, attribs.BindOptions{Environ: environ("START=3")})
+		assert.NoError(t, err)
+		assert.Equal(t, &Span{Start: 3}, value)
+	})
+
+	t.Run("test flags registered by hand", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		fs.Int("span.start", 0, "start")
+		fs.String("unrelated", "", "not a field")
+		assert.NoError(t, fs.Parse([]string{"-span.start=7", "-unrelated=x"}))
+
+		value, err := d.Bind(Component{}, attribs.BindOptions{Environ: environ(), Flags: fs})
+		assert.NoError(t, err)
+		assert.Equal(t, Span{Start: 7}, value.Span)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		for _, item := range []struct {
+			options  attribs.BindOptions
+			expected string
+		}{
+			{options: attribs.BindOptions{Environ: environ("SPAN_START=x")}, expected: "environment variable SPAN_START: invalid value for start"},
+			{options: attribs.BindOptions{Environ: environ("TAGS=[a")}, expected: "environment variable TAGS: "},
+			{options: attribs.BindOptions{Environ: environ("TAGS=a")}, expected: "environment variable TAGS: invalid value for tags: expected positional array [...]"},
+			{options: attribs.BindOptions{Environ: environ("EXTRA=a")}, expected: "environment variable EXTRA: invalid value for extra: expected positional object (...)"},
+			{options: attribs.BindOptions{Environ: environ(), Flags: flags("-verbose=maybe")}, expected: "flag -verbose: "},
+			{options: attribs.BindOptions{Environ: environ(), Input: "unknown=1"}, expected: "unknown attribute unknown"},
+		} {
+			_, err := d.Bind(Component{}, item.options)
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), item.expected)
+				assert.NotContains(t, err.Error(), "Position: 0, Length: 0")
+			}
+		}
+
+		_, err := d.Bind(Component{}, attribs.BindOptions{Environ: environ(), Input: "unknown=1", IgnoreUnknown: true})
+		assert.NoError(t, err)
+	})
+
+	t.Run("test register flags", func(t *testing.T) {
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		d.RegisterFlags(fs)
+
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) { names = append(names, f.Name) })
+		assert.Equal(t, []string{"debug", "extra", "limit.end", "limit.start", "name", "node.children", "node.name",
+			"span.end", "span.start", "tags", "verbose"}, names)
+	})
+
+	t.Run("test register flags usage", func(t *testing.T) {
+		type Options struct {
+			Name  string   `attr:"name=name, help='name of component'"`
+			Tags  []string `attr:"name=tags"`
+			Limit *Span    `attr:"name=limit"`
+		}
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		attribs.Must(attribs.New(Options{})).RegisterFlags(fs)
+
+		for name, usage := range map[string]string{
+			"name":        "name of component",
+			"tags":        "array of string",
+			"limit.start": "integer",
+		} {
+			if f := fs.Lookup(name); assert.NotNil(t, f, name) {
+				assert.Equal(t, usage, f.Usage, name)
+			}
+		}
+	})
+}
@@ -0,0 +1,409 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+// runCheck validates every input against schema given as JSON Schema file or Go type
+func runCheck(e *env, args []string) error {
+	var flags inputFlags
+	fs := e.newFlagSet("check")
+	flags.register(fs, true)
+	schemaFile := fs.String("schema", "", "JSON Schema file")
+	typeName := fs.String("type", "", "name of Go struct type")
+	pkg := fs.String("pkg", ".", "directory of Go package with -type")
+	tag := fs.String("tag", "attr", "struct tag key with attribute names of -type")
+	nameSource := fs.String("name-source", "", "struct tag key (json, yaml) with names of -type fields without -tag")
+	ignoreUnknown := fs.Bool("ignore-unknown", false, "ignore unknown attributes")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		s   *schema
+		err error
+	)
+	switch {
+	case *schemaFile != "" && *typeName != "":
+		return errors.New("-schema and -type cannot be used together")
+	case *schemaFile != "":
+		s, err = loadJSONSchema(*schemaFile)
+	case *typeName != "":
+		s, err = loadGoSchema(*pkg, *typeName, *tag, *nameSource)
+	default:
+		return errors.New("-schema or -type is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	inputs, err := e.readInputs(fs.Args(), flags.lines)
+	if err != nil {
+		return err
+	}
+
+	c := checker{ignoreUnknown: *ignoreUnknown}
+	for _, in := range inputs {
+		parsed := e.parse(in, flags.options())
+		if parsed == nil {
+			continue
+		}
+		if err := c.check(s, parsed); err != nil {
+			e.report(in, err)
+		}
+	}
+	return nil
+}
+
+// Schema types
+const (
+	typeString  = "string"
+	typeInteger = "integer"
+	typeNumber  = "number"
+	typeBoolean = "boolean"
+	typeObject  = "object"
+	typeArray   = "array"
+	typeNull    = "null"
+)
+
+// schema describes allowed values of attribute. It's common representation of JSON Schema and Go types.
+type schema struct {
+	// Types allowed for value, empty means any value
+	Types []string
+
+	// Nullable allows null value
+	Nullable bool
+
+	// Enum holds allowed values (strings, float64 numbers and booleans), empty means any value
+	Enum []any
+
+	// Bits and Unsigned restrict range of integers and floats (Bits 0 means 64)
+	Bits     int
+	Unsigned bool
+
+	// BigType is Go type of numbers without range (big.Int, big.Float, big.Rat and attribs.Number), literals are
+	// checked by parser of that type
+	BigType string
+
+	// GoType is name of Go type used in range errors
+	GoType string
+
+	// Properties of object, Required lists properties that must be present (only JSON Schema has them)
+	Properties map[string]*schema
+	Required   []string
+
+	// Positional holds schemas of positional values in object by position
+	Positional map[int]*schema
+
+	// Additional is schema of properties not listed in Properties, nil with Closed means they are unknown
+	Additional *schema
+	Closed     bool
+
+	// Struct disallows quoted keys (Go struct fields are identifiers)
+	Struct bool
+
+	// Items is schema of array items
+	Items *schema
+}
+
+// names returns names of properties, they are candidates of "did you mean" suggestions
+func (s *schema) names() []string {
+	result := make([]string, 0, len(s.Properties))
+	for name := range s.Properties {
+		result = append(result, name)
+	}
+	return result
+}
+
+// checker validates parsed attributes against schema with the same rules as attribs.Definition. JSON Schema can
+// say more than Go types, so schemas from -schema may reject strings the library accepts: Required properties
+// must be present (Definition.Parse doesn't enforce required) and values must be one of Enum.
+type checker struct {
+	ignoreUnknown bool
+}
+
+// check checks top-level attribute
+func (c checker) check(s *schema, parsed *parser.Attribute) error {
+	if parsed.Object == nil {
+		parsed = &parser.Attribute{Span: parsed.Span, Object: &parser.Attributes{Span: parsed.Span}}
+	}
+	return c.checkAttribute(s, parsed)
+}
+
+func (c checker) checkAttribute(s *schema, a *parser.Attribute) error {
+	if s == nil {
+		return nil
+	}
+	if a.Value != nil && a.Value.Null {
+		if s.Nullable || len(s.Types) == 0 {
+			return nil
+		}
+		if a.Name == "" {
+			return parser.NewParseError(a.Value.Span, "cannot set null: %s is not nullable", s.typeName(joinTypes(s.Types)))
+		}
+		return parser.NewParseError(a.Value.Span, "cannot set null to %s: %s is not nullable", a.Name, s.typeName(joinTypes(s.Types)))
+	}
+	if len(s.Types) == 0 {
+		return c.checkEnum(s, a)
+	}
+
+	var first error
+	for _, typ := range s.Types {
+		err := c.checkType(s, typ, a)
+		if err == nil {
+			return c.checkEnum(s, a)
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	if len(s.Types) == 1 {
+		return first
+	}
+	return invalidValue(a, "expected "+joinTypes(s.Types))
+}
+
+func (c checker) checkType(s *schema, typ string, a *parser.Attribute) error {
+	switch typ {
+	case typeString:
+		if a.Value == nil || a.Value.String == nil {
+			return invalidValue(a, "expected string")
+		}
+	case typeBoolean:
+		if a.Value == nil || a.Value.Boolean == nil {
+			return invalidValue(a, "expected boolean")
+		}
+	case typeInteger:
+		if a.Value == nil || a.Value.Number == nil {
+			return invalidValue(a, "expected integer")
+		}
+		return c.checkInteger(s, a)
+	case typeNumber:
+		if a.Value == nil || a.Value.Number == nil {
+			return invalidValue(a, "expected number")
+		}
+		return c.checkNumber(s, a)
+	case typeArray:
+		if a.Array == nil {
+			return invalidValue(a, "expected array")
+		}
+		for _, item := range a.Array.Attributes {
+			if err := c.checkAttribute(s.Items, item); err != nil {
+				return fmt.Errorf("cannot set array value for %s: %w", a.Name, err)
+			}
+		}
+	case typeNull:
+		// null values are accepted before types are checked
+		return invalidValue(a, "expected null")
+	case typeObject:
+		if a.Object == nil {
+			return invalidValue(a, "expected object")
+		}
+		return c.checkObject(s, a)
+	}
+	return nil
+}
+
+func (c checker) checkInteger(s *schema, a *parser.Attribute) error {
+	bits := s.Bits
+	if bits == 0 {
+		bits = 64
+	}
+	literal := *a.Value.Number
+	if s.Unsigned {
+		if _, err := parser.ParseUint(literal, bits); err == nil {
+			return nil
+		} else if _, signedErr := parser.ParseInt(literal, 64); !errors.Is(err, strconv.ErrRange) && signedErr != nil && !errors.Is(signedErr, strconv.ErrRange) {
+			return invalidValue(a, "")
+		}
+		return parser.NewParseError(a.Value.Span, "value %s out of range for %s: 0..%d", literal, s.typeName(typeInteger), ^uint64(0)>>(64-bits))
+	}
+	if _, err := parser.ParseInt(literal, bits); err == nil {
+		return nil
+	} else if !errors.Is(err, strconv.ErrRange) {
+		return invalidValue(a, "")
+	}
+	minimum := int64(-1) << (bits - 1)
+	return parser.NewParseError(a.Value.Span, "value %s out of range for %s: %d..%d", literal, s.typeName(typeInteger), minimum, -(minimum + 1))
+}
+
+func (c checker) checkNumber(s *schema, a *parser.Attribute) error {
+	literal := *a.Value.Number
+	var err error
+	switch s.BigType {
+	case "":
+	case "big.Int":
+		_, err = parser.ParseBigInt(literal)
+	case "big.Float":
+		_, err = parser.ParseBigFloat(literal, 0)
+	case "big.Rat":
+		_, err = parser.ParseBigRat(literal)
+	}
+	if err != nil {
+		return parser.NewParseError(a.Value.Span, "invalid value %s for %s: %s", literal, a.Name, s.BigType)
+	}
+	if s.BigType != "" {
+		return nil
+	}
+
+	maximum := math.MaxFloat64
+	if s.Bits == 32 {
+		maximum = math.MaxFloat32
+	}
+	value, err := parser.ParseFloat(literal, 64)
+	if err != nil && !errors.Is(err, strconv.ErrRange) {
+		return invalidValue(a, "")
+	}
+	if err != nil || math.Abs(value) > maximum {
+		return parser.NewParseError(a.Value.Span, "value %s out of range for %s: ±%g", literal, s.typeName(typeNumber), maximum)
+	}
+	return nil
+}
+
+func (c checker) checkObject(s *schema, a *parser.Attribute) error {
+	attrs, err := a.Object.ExpandPaths()
+	if err != nil {
+		return err
+	}
+
+	seen := map[string]bool{}
+	positionalIndex := 0
+	for _, att := range attrs.Attributes {
+		if att.Quoted && s.Struct {
+			return parser.NewParseError(att.Span, "quoted key %q is not allowed in struct %s", att.Name, a.Name)
+		}
+
+		var prop *schema
+		switch {
+		case att.Name == "" && !att.Quoted:
+			prop = s.Positional[positionalIndex]
+			if prop == nil && !c.ignoreUnknown {
+				if !s.Struct && s.Additional != nil {
+					return parser.NewParseError(att.Span, "expected key for map %s", a.Name)
+				}
+				return parser.NewParseError(att.Span, "unexpected positional argument at index %d", positionalIndex)
+			}
+			positionalIndex++
+		default:
+			var ok bool
+			if prop, ok = s.Properties[att.Name]; !ok {
+				prop = s.Additional
+				if prop == nil && s.Closed && !c.ignoreUnknown {
+					return parser.NewUnknownAttributeError(att.Span, att.Name, parser.Suggest(att.Name, s.names()))
+				}
+			}
+			seen[att.Name] = true
+		}
+
+		if err := c.checkAttribute(prop, att); err != nil {
+			if !s.Struct && s.Additional != nil && prop == s.Additional {
+				return fmt.Errorf("cannot set map value for %s: %w", a.Name, err)
+			}
+			return err
+		}
+	}
+
+	for _, name := range s.Required {
+		if !seen[name] {
+			if a.Name == "" {
+				return parser.NewParseError(a.Span, "missing required attribute %s", name)
+			}
+			return parser.NewParseError(a.Span, "missing required attribute %s in %s", name, a.Name)
+		}
+	}
+	return nil
+}
+
+// checkEnum checks that scalar value is one of enum values
+func (c checker) checkEnum(s *schema, a *parser.Attribute) error {
+	if len(s.Enum) == 0 {
+		return nil
+	}
+	for _, allowed := range s.Enum {
+		if a.Value == nil {
+			break
+		}
+		switch allowed := allowed.(type) {
+		case string:
+			if a.Value.String != nil && *a.Value.String == allowed {
+				return nil
+			}
+		case bool:
+			if a.Value.Boolean != nil && *a.Value.Boolean == strconv.FormatBool(allowed) {
+				return nil
+			}
+		case float64:
+			if a.Value.Number == nil {
+				continue
+			}
+			if value, err := parser.ParseFloat(*a.Value.Number, 64); err == nil && value == allowed {
+				return nil
+			}
+		}
+	}
+	return invalidValue(a, "must be one of "+formatEnum(s.Enum))
+}
+
+// typeName returns Go type name for errors when schema was created from Go type
+func (s *schema) typeName(typ string) string {
+	if s.GoType != "" {
+		return s.GoType
+	}
+	return typ
+}
+
+func joinTypes(types []string) string {
+	result := ""
+	for i, typ := range types {
+		switch {
+		case i == 0:
+		case i == len(types)-1:
+			result += " or "
+		default:
+			result += ", "
+		}
+		result += typ
+	}
+	return result
+}
+
+func formatEnum(values []any) string {
+	result := ""
+	for i, value := range values {
+		if i > 0 {
+			result += ", "
+		}
+		if s, ok := value.(string); ok {
+			// positional string value is always written quoted
+			result += parser.Print(&parser.Attribute{Value: &parser.Value{String: &s}}, parser.PrintOptions{})
+		} else {
+			result += fmt.Sprint(value)
+		}
+	}
+	return result
+}
+
+// invalidValue returns error for value of attribute with optional reason
+func invalidValue(a *parser.Attribute, reason string) error {
+	message := "invalid value"
+	if a.Name != "" {
+		message += " for " + a.Name
+	}
+	if reason != "" {
+		message += ": " + reason
+	}
+	return parser.NewParseError(valueSpan(a), "%s", message)
+}
+
+// valueSpan returns span of scalar value of attribute, or span of attribute itself for objects and arrays
+func valueSpan(a *parser.Attribute) *parser.SourceSpan {
+	if a.Value != nil {
+		return a.Value.Span
+	}
+	return a.Span
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/cmd/attribs/testdata/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	t.Run("test json schema", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			args     []string
+			expected string
+		}{
+			{input: "name=x"},
+			{input: "name=x, mode=fast, port=80, ratio=0.5, tags['a', b], env(HOME='/root'), child(enabled, child(enabled=false))"},
+			{input: "name=x, ratio=null"},
+			{input: "name=x, other=1", expected: "<stdin>:1:9: unknown attribute other"},
+			{input: "name=x, other=1", args: []string{"-ignore-unknown"}},
+			{input: "port=80", expected: "<stdin>:1:1: missing required attribute name"},
+			{input: "name=1", expected: "<stdin>:1:6: invalid value for name: expected string"},
+			{input: "name=x, mode=medium", expected: "<stdin>:1:14: invalid value for mode: must be one of 'fast', 'slow'"},
+			{input: "name=x, port=1.5", expected: "<stdin>:1:14: invalid value for port"},
+			{input: "name=x, ratio=x", expected: "<stdin>:1:15: invalid value for ratio: expected number or null"},
+			{input: "name=x, tags[a, 1]", expected: "<stdin>:1:17: cannot set array value for tags: invalid value: expected string"},
+			{input: "name=x, env(HOME=1)", expected: "<stdin>:1:18: cannot set map value for env: invalid value for HOME: expected string"},
+			{input: "name=x, child(child(enabled=1))", expected: "<stdin>:1:29: invalid value for enabled: expected boolean"},
+			{input: "name=null", expected: "<stdin>:1:6: cannot set null to name: string is not nullable"},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				args := append([]string{"check", "-schema", "testdata/schema.json"}, item.args...)
+				code, _, stderr := runTest(item.input, args...)
+				if item.expected == "" {
+					assert.Equal(t, 0, code, stderr)
+				} else {
+					assert.Equal(t, 1, code)
+					assert.Contains(t, stderr, item.expected+"\n")
+				}
+			})
+		}
+	})
+
+	t.Run("test go type", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+		}{
+			{input: "name=x"},
+			{input: "'/tmp', name=x, port=65535, level=debug, tags[a], labels(a=1), child(name=y, child=null), id=1, Extra(a[1])"},
+			{input: "Base(id=1), id=2"},
+			{input: "port=65536", expected: "<stdin>:1:6: value 65536 out of range for uint16: 0..65535"},
+			{input: "port=-1", expected: "<stdin>:1:6: value -1 out of range for uint16: 0..65535"},
+			{input: "level=1", expected: "<stdin>:1:7: invalid value for level: expected string"},
+			{input: "skip=x", expected: "<stdin>:1:1: unknown attribute skip"},
+			{input: "'/a', '/b'", expected: "<stdin>:1:6: unexpected positional argument at index 1"},
+			{input: "'Name'=x", expected: "<stdin>:1:1: quoted key \"Name\" is not allowed in struct"},
+			{input: "labels(1)", expected: "<stdin>:1:8: expected key for map labels"},
+			{input: "child(port=1e3)", expected: "<stdin>:1:12: invalid value for port"},
+			{input: "name=null", expected: "<stdin>:1:6: cannot set null to name: string is not nullable"},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				code, _, stderr := runTest(item.input, "check", "-type", "Config", "-pkg", "testdata/types")
+				if item.expected == "" {
+					assert.Equal(t, 0, code, stderr)
+				} else {
+					assert.Equal(t, 1, code)
+					assert.Contains(t, stderr, item.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("test go type agrees with definition", func(t *testing.T) {
+		d := attribs.Must(attribs.New(types.Numbers{}))
+		for _, item := range []struct {
+			input    string
+			expected string
+		}{
+			{input: "ratio=3.4e38, weight=1e308, small=-128"},
+			{input: "ratio=1e39", expected: "value 1e39 out of range for float32: ±3.4028234663852886e+38"},
+			{input: "ratio=-1e39", expected: "value -1e39 out of range for float32: ±3.4028234663852886e+38"},
+			{input: "weight=1e309", expected: "value 1e309 out of range for float64: ±1.7976931348623157e+308"},
+			{input: "small=128", expected: "value 128 out of range for int8: -128..127"},
+			{input: "count=123456789012345678901234567890, total=1e400, share=0.1, raw=1e999"},
+			{input: "count=0x10, count=null, share=null"},
+			{input: "count=1.5", expected: "invalid value 1.5 for count: big.Int"},
+			{input: "total=x", expected: "invalid value for total"},
+			{input: "raw=x", expected: "invalid value for raw"},
+			{input: "amount()"},
+			{input: "amount=1", expected: "amount"},
+			{input: "values[1, 1e999, 0x10]"},
+			{input: "values[x]", expected: "invalid value"},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				code, _, stderr := runTest(item.input, "check", "-type", "Numbers", "-pkg", "testdata/types")
+				_, err := d.Parse(item.input, false)
+				if item.expected == "" {
+					assert.NoError(t, err)
+					assert.Equal(t, 0, code, stderr)
+				} else {
+					assert.ErrorContains(t, err, item.expected)
+					assert.Equal(t, 1, code)
+					assert.Contains(t, stderr, item.expected)
+				}
+			})
+		}
+	})
+
+	t.Run("test name source", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+		}{
+			{input: "max_length=10, title=x, Plain"},
+			{input: "label=x", expected: "<stdin>:1:1: unknown attribute label"},
+			{input: "Internal=x", expected: "<stdin>:1:1: unknown attribute Internal"},
+			{input: "MaxLength=1", expected: "<stdin>:1:1: unknown attribute MaxLength, did you mean max_length?"},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				code, _, stderr := runTest(item.input, "check", "-type", "DTO", "-pkg", "testdata/types", "-name-source", "json")
+				if item.expected == "" {
+					assert.Equal(t, 0, code, stderr)
+				} else {
+					assert.Equal(t, 1, code)
+					assert.Contains(t, stderr, item.expected)
+				}
+			})
+		}
+
+		code, _, _ := runTest("MaxLength=1", "check", "-type", "DTO", "-pkg", "testdata/types")
+		assert.Equal(t, 0, code)
+
+		// fields with names that are not attribute names are skipped
+		code, _, stderr := runTest("value=x", "check", "-type", "HeaderDTO", "-pkg", "testdata/types", "-name-source", "json")
+		assert.Equal(t, 0, code, stderr)
+		for _, input := range []string{"Type=x", "Dash=x"} {
+			code, _, stderr = runTest(input, "check", "-type", "HeaderDTO", "-pkg", "testdata/types", "-name-source", "json")
+			assert.Equal(t, 1, code)
+			assert.Contains(t, stderr, "unknown attribute "+input[:len(input)-2])
+		}
+	})
+
+	t.Run("test invalid schema", func(t *testing.T) {
+		for _, item := range []struct {
+			args     []string
+			expected string
+		}{
+			{args: nil, expected: "-schema or -type is required"},
+			{args: []string{"-schema", "testdata/schema.json", "-type", "Config"}, expected: "cannot be used together"},
+			{args: []string{"-schema", "testdata/missing.json"}, expected: "no such file"},
+			{args: []string{"-type", "Missing", "-pkg", "testdata/types"}, expected: "type Missing not found"},
+			{args: []string{"-type", "NotStruct", "-pkg", "testdata/types"}, expected: "type NotStruct is not struct"},
+			{args: []string{"-type", "Invalid", "-pkg", "testdata/types"}, expected: "invalid attr tag: attribute name is required"},
+		} {
+			code, _, stderr := runTest("name=x", append([]string{"check"}, item.args...)...)
+			assert.Equal(t, 2, code)
+			assert.Contains(t, stderr, item.expected)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+// runFmt prints every input in canonical form, with -w it rewrites files instead
+func runFmt(e *env, args []string) error {
+	var flags inputFlags
+	fs := e.newFlagSet("fmt")
+	flags.register(fs, false)
+	write := fs.Bool("w", false, "write result to file instead of stdout")
+	compact := fs.Bool("compact", false, "write without spaces")
+	multiline := fs.Bool("multiline", false, "write every attribute on its own line")
+	indent := fs.String("indent", "\t", "indentation with -multiline")
+	quote := fs.String("quote", "single", "quotes of strings: single, double or auto")
+	trailingComma := fs.String("trailing-comma", "never", "trailing comma: never, multiline or always")
+	sortKeys := fs.Bool("sort", false, "sort attributes by name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *write && (fs.NArg() == 0 || flags.lines) {
+		return fmt.Errorf("-w needs files and cannot be used with -lines")
+	}
+
+	options := parser.PrintOptions{Indent: *indent, SortKeys: *sortKeys}
+	switch {
+	case *compact && *multiline:
+		return fmt.Errorf("-compact and -multiline cannot be used together")
+	case *compact:
+		options.Layout = parser.LayoutCompact
+	case *multiline:
+		options.Layout = parser.LayoutMultiline
+	}
+	switch *quote {
+	case "single":
+		options.Quote = parser.QuoteSingle
+	case "double":
+		options.Quote = parser.QuoteDouble
+	case "auto":
+		options.Quote = parser.QuoteAuto
+	default:
+		return fmt.Errorf("invalid -quote %q", *quote)
+	}
+	switch *trailingComma {
+	case "never":
+		options.TrailingComma = parser.TrailingCommaNever
+	case "multiline":
+		options.TrailingComma = parser.TrailingCommaMultiline
+	case "always":
+		options.TrailingComma = parser.TrailingCommaAlways
+	default:
+		return fmt.Errorf("invalid -trailing-comma %q", *trailingComma)
+	}
+
+	inputs, err := e.readInputs(fs.Args(), flags.lines)
+	if err != nil {
+		return err
+	}
+
+	for _, in := range inputs {
+		// trailing commas are accepted, so output of fmt can be formatted again
+		parsed := e.parse(in, parser.Options{TrailingCommas: true, RejectStrayCharacters: true})
+		if parsed == nil {
+			continue
+		}
+		formatted := parser.Print(parsed, options)
+		if !*write {
+			fmt.Fprintln(e.stdout, formatted)
+			continue
+		}
+		if formatted == in.text {
+			continue
+		}
+		info, err := os.Stat(in.name)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(in.name, []byte(formatted+"\n"), info.Mode()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFmt(t *testing.T) {
+	t.Run("test stdout", func(t *testing.T) {
+		code, stdout, stderr := runTest("a = 1 ,b\nc=(", "fmt", "-lines")
+		assert.Equal(t, 1, code)
+		assert.Equal(t, "a=1, b\n", stdout)
+		assert.Contains(t, stderr, "<stdin>:2:3:")
+	})
+
+	t.Run("test options", func(t *testing.T) {
+		for _, item := range []struct {
+			args     []string
+			expected string
+		}{
+			{args: nil, expected: "z=1, a(b='it\\'s')\n"},
+			{args: []string{"-compact", "-quote", "auto"}, expected: "z=1,a(b=\"it's\")\n"},
+			{args: []string{"-sort", "-quote", "single"}, expected: "a(b='it\\'s'), z=1\n"},
+			{args: []string{"-multiline", "-indent", "  ", "-trailing-comma", "multiline"}, expected: "z=1,\na(\n  b='it\\'s',\n),\n"},
+			{args: []string{"-trailing-comma", "always", "-quote", "double"}, expected: "z=1, a(b=\"it's\",),\n"},
+		} {
+			code, stdout, stderr := runTest(`z = 1, a(b="it's",),`, append([]string{"fmt"}, item.args...)...)
+			require.Equal(t, 0, code, stderr)
+			assert.Equal(t, item.expected, stdout, "args: %v", item.args)
+		}
+
+		for _, args := range [][]string{{"-compact", "-multiline"}, {"-quote", "backtick"}, {"-trailing-comma", "sometimes"}} {
+			code, _, _ := runTest("a=1", append([]string{"fmt"}, args...)...)
+			assert.Equal(t, 2, code, "args: %v", args)
+		}
+	})
+
+	t.Run("test write", func(t *testing.T) {
+		dir := t.TempDir()
+		file := filepath.Join(dir, "tag.txt")
+		require.NoError(t, os.WriteFile(file, []byte("a = 1 ,b\n"), 0o644))
+
+		code, stdout, stderr := runTest("", "fmt", "-w", file)
+		require.Equal(t, 0, code, stderr)
+		assert.Empty(t, stdout)
+
+		data, err := os.ReadFile(file)
+		require.NoError(t, err)
+		assert.Equal(t, "a=1, b\n", string(data))
+	})
+
+	t.Run("test write needs files", func(t *testing.T) {
+		code, _, stderr := runTest("a=1", "fmt", "-w")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "-w needs files")
+	})
+}
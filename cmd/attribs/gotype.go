@@ -0,0 +1,310 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/phonkee/attribs"
+)
+
+// loadGoSchema loads schema of struct type declared in Go package in dir. Fields are mapped the same way
+// attribs.New maps them (names from tag, disabled and positional fields, embedded structs). Of types from other
+// packages only big.Int, big.Float, big.Rat and attribs.Number are known, others are not resolved and accept any
+// value. Fields without tag take names from nameSource tag same as attribs.Options.NameSource.
+func loadGoSchema(dir, typeName, tag, nameSource string) (*schema, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	l := &goLoader{
+		fset:       token.NewFileSet(),
+		specs:      map[string]*ast.TypeSpec{},
+		schemas:    map[string]*schema{},
+		imports:    map[*token.File]map[string]string{},
+		tag:        tag,
+		nameSource: nameSource,
+	}
+	for _, file := range files {
+		if strings.HasSuffix(file, "_test.go") {
+			continue
+		}
+		parsed, err := parser.ParseFile(l.fset, file, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		l.imports[l.fset.File(parsed.Pos())] = fileImports(parsed)
+		for _, decl := range parsed.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				spec := spec.(*ast.TypeSpec)
+				l.specs[spec.Name.Name] = spec
+			}
+		}
+	}
+
+	spec, ok := l.specs[typeName]
+	if !ok {
+		return nil, fmt.Errorf("type %s not found in %s", typeName, dir)
+	}
+	if _, ok := spec.Type.(*ast.StructType); !ok {
+		return nil, fmt.Errorf("type %s is not struct", typeName)
+	}
+	return l.named(spec.Name)
+}
+
+// goLoader converts Go type expressions to schema
+type goLoader struct {
+	fset    *token.FileSet
+	specs   map[string]*ast.TypeSpec
+	schemas map[string]*schema
+	tag     string
+
+	// imports of every file by package name (big: math/big)
+	imports map[*token.File]map[string]string
+
+	// nameSource is tag key with names of fields without tag
+	nameSource string
+}
+
+// basic types with bit size of integers and floats
+var goBasicTypes = map[string]schema{
+	"string":  {Types: []string{typeString}},
+	"bool":    {Types: []string{typeBoolean}},
+	"int":     {Types: []string{typeInteger}, Bits: strconv.IntSize},
+	"int8":    {Types: []string{typeInteger}, Bits: 8},
+	"int16":   {Types: []string{typeInteger}, Bits: 16},
+	"int32":   {Types: []string{typeInteger}, Bits: 32},
+	"rune":    {Types: []string{typeInteger}, Bits: 32},
+	"int64":   {Types: []string{typeInteger}, Bits: 64},
+	"uint":    {Types: []string{typeInteger}, Bits: strconv.IntSize, Unsigned: true},
+	"uint8":   {Types: []string{typeInteger}, Bits: 8, Unsigned: true},
+	"byte":    {Types: []string{typeInteger}, Bits: 8, Unsigned: true},
+	"uint16":  {Types: []string{typeInteger}, Bits: 16, Unsigned: true},
+	"uint32":  {Types: []string{typeInteger}, Bits: 32, Unsigned: true},
+	"uint64":  {Types: []string{typeInteger}, Bits: 64, Unsigned: true},
+	"float32": {Types: []string{typeNumber}, Bits: 32},
+	"float64": {Types: []string{typeNumber}, Bits: 64},
+}
+
+// goPackageTypes are types from other packages that attribs.New handles by type, not by kind
+var goPackageTypes = map[string]schema{
+	"math/big.Int":                      {Types: []string{typeNumber}, BigType: "big.Int"},
+	"math/big.Float":                    {Types: []string{typeNumber}, BigType: "big.Float"},
+	"math/big.Rat":                      {Types: []string{typeNumber}, BigType: "big.Rat"},
+	"github.com/phonkee/attribs.Number": {Types: []string{typeNumber}, BigType: "attribs.Number"},
+}
+
+func (l *goLoader) load(expr ast.Expr) (*schema, error) {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		if expr.Name == "any" {
+			return &schema{Nullable: true}, nil
+		}
+		if basic, ok := goBasicTypes[expr.Name]; ok {
+			basic.GoType = expr.Name
+			return &basic, nil
+		}
+		return l.named(expr)
+	case *ast.ParenExpr:
+		return l.load(expr.X)
+	case *ast.StarExpr:
+		elem, err := l.load(expr.X)
+		if err != nil {
+			return nil, err
+		}
+		return nullable(elem), nil
+	case *ast.ArrayType:
+		items, err := l.load(expr.Elt)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Types: []string{typeArray}, Nullable: expr.Len == nil, Items: items}, nil
+	case *ast.MapType:
+		if key, ok := expr.Key.(*ast.Ident); !ok || key.Name != "string" {
+			return nil, fmt.Errorf("%s: %w: %s", l.fset.Position(expr.Pos()), attribs.ErrMapKeyNotStr, types.ExprString(expr.Key))
+		}
+		elem, err := l.load(expr.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &schema{Types: []string{typeObject}, Nullable: true, Additional: elem}, nil
+	case *ast.InterfaceType:
+		return &schema{Nullable: true}, nil
+	case *ast.StructType:
+		result := &schema{}
+		return result, l.loadStruct(result, expr)
+	case *ast.SelectorExpr:
+		if pkg, ok := expr.X.(*ast.Ident); ok {
+			if known, ok := goPackageTypes[l.imports[l.fset.File(expr.Pos())][pkg.Name]+"."+expr.Sel.Name]; ok {
+				known.GoType = known.BigType
+				return &known, nil
+			}
+		}
+		// other types from other packages are not resolved
+		return &schema{}, nil
+	case *ast.IndexExpr, *ast.IndexListExpr:
+		// generic types are not resolved
+		return &schema{}, nil
+	default:
+		return nil, fmt.Errorf("%s: %w: %s", l.fset.Position(expr.Pos()), attribs.ErrUnsupportedType, types.ExprString(expr))
+	}
+}
+
+// named returns schema of type declared in package, structs are cached so recursive types are supported
+func (l *goLoader) named(ident *ast.Ident) (*schema, error) {
+	if existing, ok := l.schemas[ident.Name]; ok {
+		return existing, nil
+	}
+	spec, ok := l.specs[ident.Name]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w: %s", l.fset.Position(ident.Pos()), attribs.ErrUnsupportedType, ident.Name)
+	}
+
+	if structType, ok := spec.Type.(*ast.StructType); ok {
+		result := &schema{}
+		l.schemas[ident.Name] = result
+		return result, l.loadStruct(result, structType)
+	}
+
+	result, err := l.load(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+	if result.BigType != "" && spec.Assign == token.NoPos {
+		// defined type loses special handling, attribs.Number is string underneath and big numbers are structs
+		// without exported fields
+		if result.BigType == "attribs.Number" {
+			return &schema{Types: []string{typeString}}, nil
+		}
+		return &schema{Types: []string{typeObject}, Struct: true, Closed: true}, nil
+	}
+	if result.GoType != "" {
+		// copy, so basic type keeps its name
+		named := *result
+		named.GoType = fmt.Sprintf("%s (%s)", ident.Name, result.GoType)
+		result = &named
+	}
+	return result, nil
+}
+
+// loadStruct fills schema with fields of struct
+func (l *goLoader) loadStruct(result *schema, structType *ast.StructType) error {
+	result.Types = []string{typeObject}
+	result.Struct = true
+	result.Closed = true
+	result.Properties = map[string]*schema{}
+	result.Positional = map[int]*schema{}
+
+	for _, field := range structType.Fields.List {
+		names := field.Names
+		embedded := len(names) == 0
+		if embedded {
+			ident := embeddedIdent(field.Type)
+			if ident == nil {
+				continue
+			}
+			names = []*ast.Ident{ident}
+		}
+
+		options, err := l.fieldOptions(field)
+		if err != nil {
+			return err
+		}
+		if options.Disabled {
+			continue
+		}
+
+		fieldSchema, err := l.load(field.Type)
+		if err != nil {
+			return err
+		}
+
+		if embedded {
+			for name, prop := range fieldSchema.Properties {
+				if _, ok := result.Properties[name]; ok {
+					return fmt.Errorf("%s: %w: %v", l.fset.Position(field.Pos()), attribs.ErrDuplicateField, name)
+				}
+				result.Properties[name] = prop
+			}
+		}
+
+		for _, name := range names {
+			if !name.IsExported() {
+				continue
+			}
+			alias := options.Name
+			if alias == "" {
+				alias = name.Name
+			}
+			result.Properties[alias] = fieldSchema
+			if options.Position >= 0 {
+				result.Positional[options.Position] = fieldSchema
+			}
+		}
+	}
+	return nil
+}
+
+// fieldOptions reads options of field from its struct tags with attribs.ParseStructTag, so fields are mapped by
+// the same rules New uses
+func (l *goLoader) fieldOptions(field *ast.Field) (attribs.TagOptions, error) {
+	if field.Tag == nil {
+		return attribs.TagOptions{Position: -1}, nil
+	}
+	literal, err := strconv.Unquote(field.Tag.Value)
+	if err != nil {
+		return attribs.TagOptions{}, err
+	}
+	options, err := attribs.ParseStructTag(reflect.StructTag(literal), l.tag, l.nameSource)
+	if err != nil {
+		return options, fmt.Errorf("%s: invalid %s tag: %w", l.fset.Position(field.Tag.Pos()), l.tag, err)
+	}
+	return options, nil
+}
+
+// fileImports returns import paths of file by package name, name is the last element of path when import has none
+func fileImports(file *ast.File) map[string]string {
+	result := make(map[string]string, len(file.Imports))
+	for _, spec := range file.Imports {
+		path, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		result[name] = path
+	}
+	return result
+}
+
+// embeddedIdent returns identifier of embedded field type (T or *T)
+func embeddedIdent(expr ast.Expr) *ast.Ident {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	ident, _ := expr.(*ast.Ident)
+	return ident
+}
+
+// nullable returns copy of schema that accepts null
+func nullable(s *schema) *schema {
+	if s.Nullable {
+		return s
+	}
+	result := *s
+	result.Nullable = true
+	return &result
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+// env holds streams of running command and whether any input failed
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	failed bool
+}
+
+// input is single attribute string read from file or stdin
+type input struct {
+	// name of file (<stdin> for standard input)
+	name string
+	// text of attribute string
+	text string
+	// line in file where text starts (1-based), used with -lines
+	line int
+}
+
+// inputFlags are flags shared by commands that read attribute strings
+type inputFlags struct {
+	comments bool
+	dedent   bool
+	lines    bool
+	syntax   parser.Syntax
+}
+
+// syntaxes are values of -syntax flag
+var syntaxes = map[string]parser.Syntax{
+	"attribs":   nil,
+	"structtag": parser.StructTagSyntax{},
+	"semicolon": parser.SemicolonSyntax{},
+}
+
+func (i *inputFlags) register(fs *flag.FlagSet, parserOptions bool) {
+	if parserOptions {
+		fs.BoolVar(&i.comments, "comments", false, "allow # // and /* */ comments")
+		fs.BoolVar(&i.dedent, "dedent", false, "dedent multi-line strings")
+		fs.Func("syntax", "input syntax: attribs, structtag (key:\"value\") or semicolon (key=value; flag)", func(value string) error {
+			syntax, ok := syntaxes[value]
+			if !ok {
+				return fmt.Errorf("unknown syntax %q", value)
+			}
+			i.syntax = syntax
+			return nil
+		})
+	}
+	fs.BoolVar(&i.lines, "lines", false, "treat every non-empty line as separate attribute string")
+}
+
+func (i *inputFlags) options() parser.Options {
+	return parser.Options{Comments: i.comments, DedentStrings: i.dedent, Syntax: i.syntax, RejectStrayCharacters: true}
+}
+
+// readInputs reads attribute strings from files (or stdin when there are no files, "-" also means stdin)
+func (e *env) readInputs(files []string, lines bool) ([]*input, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var result []*input
+	for _, file := range files {
+		var (
+			data []byte
+			err  error
+			name = file
+		)
+		if file == "-" {
+			name = "<stdin>"
+			data, err = io.ReadAll(e.stdin)
+		} else {
+			data, err = os.ReadFile(file)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		text := string(data)
+		if !lines {
+			result = append(result, &input{name: name, text: strings.TrimSuffix(text, "\n"), line: 1})
+			continue
+		}
+		for i, line := range strings.Split(text, "\n") {
+			if strings.TrimSpace(line) == "" {
+				continue
+			}
+			result = append(result, &input{name: name, text: strings.TrimSuffix(line, "\r"), line: i + 1})
+		}
+	}
+	return result, nil
+}
+
+// parse parses input, errors are reported and nil is returned
+func (e *env) parse(in *input, options parser.Options) *parser.Attribute {
+	parsed, err := parser.Parse(strings.NewReader(in.text), options)
+	if err != nil {
+		e.report(in, err)
+		return nil
+	}
+	return parsed
+}
+
+// report writes error to stderr in file:line:column: message format followed by offending line with caret
+// under the problem. Errors without span are written with file name only.
+func (e *env) report(in *input, err error) {
+	e.failed = true
+
+	var pe parser.ParseError
+	if !errors.As(err, &pe) || pe.Span() == nil || pe.Span().Start.Line == 0 {
+		fmt.Fprintf(e.stderr, "%s: %v\n", in.name, err)
+		return
+	}
+
+	span := pe.Span()
+	// errors created by parser start with span, it's redundant with position
+	message := strings.Replace(err.Error(), pe.Error(), pe.Message(), 1)
+	fmt.Fprintf(e.stderr, "%s:%d:%d: %s\n", in.name, in.line+span.Start.Line-1, span.Start.Column, message)
+
+	line := strings.Split(in.text, "\n")[span.Start.Line-1]
+	line = strings.TrimSuffix(line, "\r")
+	fmt.Fprintf(e.stderr, "\t%s\n", line)
+	fmt.Fprintf(e.stderr, "\t%s\n", caret(line, span))
+}
+
+// caret returns line marking span in line: whitespace up to start column (tabs are kept, so it's aligned) and
+// ^~~~ under span. Spans continuing on next lines are marked up to end of line.
+func caret(line string, span *parser.SourceSpan) string {
+	var sb strings.Builder
+
+	column := 1
+	for _, r := range line {
+		if column == span.Start.Column {
+			break
+		}
+		if r == '\t' {
+			sb.WriteRune('\t')
+		} else {
+			sb.WriteRune(' ')
+		}
+		column++
+	}
+
+	length := span.Length
+	if span.End.Line != span.Start.Line {
+		length = utf8.RuneCountInString(line) - span.Start.Column + 1
+	}
+	sb.WriteRune('^')
+	if length > 1 {
+		sb.WriteString(strings.Repeat("~", length-1))
+	}
+	return sb.String()
+}
@@ -0,0 +1,85 @@
+// Command attribs parses, formats and checks attribute strings.
+//
+// Usage:
+//
+//	attribs parse [-comments] [-dedent] [-lines] [file ...]
+//	attribs fmt [-lines] [-w] [file ...]
+//	attribs check (-schema file.json | -type Name [-pkg dir]) [-ignore-unknown] [file ...]
+//
+// Every file (or stdin when no file is given) holds single attribute string, with -lines every non-empty line
+// is separate attribute string. Errors are reported with file, line and column followed by offending line with
+// caret under the problem. Exit code is 1 when any input is invalid and 2 on usage error.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+)
+
+// command is single subcommand of attribs tool
+type command struct {
+	name  string
+	usage string
+	run   func(env *env, args []string) error
+}
+
+var commands = []command{
+	{name: "parse", usage: "print attribute tree as JSON", run: runParse},
+	{name: "fmt", usage: "print attribute string in canonical form", run: runFmt},
+	{name: "check", usage: "validate attribute string against JSON Schema or Go type", run: runCheck},
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// run runs attribs tool with given arguments and returns exit code
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	for _, cmd := range commands {
+		if cmd.name != args[0] {
+			continue
+		}
+		e := &env{stdin: stdin, stdout: stdout, stderr: stderr}
+		if err := cmd.run(e, args[1:]); err != nil {
+			if err != flag.ErrHelp {
+				fmt.Fprintf(stderr, "attribs %s: %v\n", cmd.name, err)
+			}
+			return 2
+		}
+		if e.failed {
+			return 1
+		}
+		return 0
+	}
+
+	if args[0] != "help" && args[0] != "-h" && args[0] != "-help" {
+		fmt.Fprintf(stderr, "attribs: unknown command %q\n", args[0])
+	}
+	usage(stderr)
+	return 2
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "Usage: attribs <command> [flags] [file ...]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(w, "  %-6s %s\n", cmd.name, cmd.usage)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Run 'attribs <command> -h' for flags of command.")
+}
+
+// newFlagSet returns flag set for command that writes its output to env
+func (e *env) newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet("attribs "+name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	return fs
+}
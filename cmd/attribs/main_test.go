@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runTest runs attribs with stdin and returns exit code, stdout and stderr
+func runTest(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRun(t *testing.T) {
+	t.Run("test usage", func(t *testing.T) {
+		for _, args := range [][]string{nil, {"unknown"}, {"help"}} {
+			code, _, stderr := runTest("", args...)
+			assert.Equal(t, 2, code)
+			assert.Contains(t, stderr, "Usage: attribs <command>")
+		}
+	})
+
+	t.Run("test invalid flag", func(t *testing.T) {
+		code, _, stderr := runTest("", "parse", "-unknown")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "flag provided but not defined")
+	})
+
+	t.Run("test missing file", func(t *testing.T) {
+		code, _, stderr := runTest("", "parse", filepath.Join(t.TempDir(), "missing"))
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, "attribs parse:")
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("test tree", func(t *testing.T) {
+		code, stdout, stderr := runTest("name='hello', flag, list[1, 2], db.port=5432\n", "parse")
+		require.Equal(t, 0, code, stderr)
+
+		var tree jsonAttribute
+		require.NoError(t, json.Unmarshal([]byte(stdout), &tree))
+		require.NotNil(t, tree.Object)
+		require.Len(t, tree.Object.Attributes, 4)
+
+		name := tree.Object.Attributes[0]
+		assert.Equal(t, "name", name.Name)
+		assert.Equal(t, "hello", *name.Value.String)
+		assert.Equal(t, 1, name.Value.Span.Start.Line)
+		assert.Equal(t, 6, name.Value.Span.Start.Column)
+
+		assert.Equal(t, "true", *tree.Object.Attributes[1].Value.Boolean)
+		assert.Len(t, tree.Object.Attributes[2].Array.Attributes, 2)
+		assert.Equal(t, []string{"db", "port"}, tree.Object.Attributes[3].Path)
+	})
+
+	t.Run("test comments", func(t *testing.T) {
+		code, stdout, stderr := runTest("# comment\nname=x", "parse", "-comments")
+		require.Equal(t, 0, code, stderr)
+
+		var tree jsonAttribute
+		require.NoError(t, json.Unmarshal([]byte(stdout), &tree))
+		require.Len(t, tree.Comments, 1)
+		assert.Equal(t, "# comment", tree.Comments[0].Text)
+	})
+
+	t.Run("test syntax", func(t *testing.T) {
+		code, stdout, stderr := runTest(`name:"x" size:"10"`, "parse", "-syntax", "structtag")
+		require.Equal(t, 0, code, stderr)
+
+		var tree jsonAttribute
+		require.NoError(t, json.Unmarshal([]byte(stdout), &tree))
+		require.Len(t, tree.Object.Attributes, 2)
+		assert.Equal(t, "x", *tree.Object.Attributes[0].Value.String)
+		assert.Equal(t, "10", *tree.Object.Attributes[1].Value.Number)
+
+		code, _, stderr = runTest("a=1; required", "parse", "-syntax", "semicolon")
+		assert.Equal(t, 0, code, stderr)
+
+		code, _, stderr = runTest("a=1", "parse", "-syntax", "xml")
+		assert.Equal(t, 2, code)
+		assert.Contains(t, stderr, `unknown syntax "xml"`)
+	})
+
+	t.Run("test lines", func(t *testing.T) {
+		code, stdout, stderr := runTest("a=1\n\nb=2\n", "parse", "-lines")
+		require.Equal(t, 0, code, stderr)
+
+		decoder := json.NewDecoder(strings.NewReader(stdout))
+		var names []string
+		for decoder.More() {
+			var tree jsonAttribute
+			require.NoError(t, decoder.Decode(&tree))
+			names = append(names, tree.Object.Attributes[0].Name)
+		}
+		assert.Equal(t, []string{"a", "b"}, names)
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		for _, item := range []struct {
+			name     string
+			input    string
+			args     []string
+			expected string
+		}{
+			{
+				name:     "double comma",
+				input:    "name=x,,y",
+				expected: "<stdin>:1:8: unexpected double comma\n\tname=x,,y\n\t       ^\n",
+			},
+			{
+				name:     "error on second line",
+				input:    "a=1,\n\tb='x\\q'",
+				expected: "<stdin>:2:6: unknown escape sequence \\q\n\t\tb='x\\q'\n\t\t    ^~\n",
+			},
+			{
+				name:     "line of input",
+				input:    "a=1\n\nb=(\n",
+				args:     []string{"-lines"},
+				expected: "<stdin>:3:3: expected value, got OPEN_BRACKET: \"(\"\n\tb=(\n\t  ^\n",
+			},
+			{
+				name:     "multi-line span",
+				input:    "a='''x\ny",
+				expected: "<stdin>:1:3: unterminated multi-line string\n\ta='''x\n\t  ^~~\n",
+			},
+		} {
+			t.Run(item.name, func(t *testing.T) {
+				code, _, stderr := runTest(item.input, append([]string{"parse"}, item.args...)...)
+				assert.Equal(t, 1, code)
+				assert.Equal(t, item.expected, stderr)
+			})
+		}
+	})
+
+	t.Run("test files", func(t *testing.T) {
+		dir := t.TempDir()
+		valid := filepath.Join(dir, "valid.txt")
+		invalid := filepath.Join(dir, "invalid.txt")
+		require.NoError(t, os.WriteFile(valid, []byte("a=1\n"), 0o644))
+		require.NoError(t, os.WriteFile(invalid, []byte("a=1,\n"), 0o644))
+
+		code, stdout, stderr := runTest("", "parse", valid, invalid)
+		assert.Equal(t, 1, code)
+		assert.Contains(t, stdout, `"name": "a"`)
+		assert.True(t, strings.HasPrefix(stderr, invalid+":1:4: trailing comma not allowed"), stderr)
+	})
+}
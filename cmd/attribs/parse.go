@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+// runParse prints attribute tree of every input as JSON document
+func runParse(e *env, args []string) error {
+	var flags inputFlags
+	fs := e.newFlagSet("parse")
+	flags.register(fs, true)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	inputs, err := e.readInputs(fs.Args(), flags.lines)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(e.stdout)
+	encoder.SetIndent("", "  ")
+	for _, in := range inputs {
+		parsed := e.parse(in, flags.options())
+		if parsed == nil {
+			continue
+		}
+		if err := encoder.Encode(newJSONAttribute(parsed)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// jsonAttribute is JSON representation of parser.Attribute
+type jsonAttribute struct {
+	Name     string         `json:"name,omitempty"`
+	Quoted   bool           `json:"quoted,omitempty"`
+	Path     []string       `json:"path,omitempty"`
+	Span     *jsonSpan      `json:"span"`
+	Value    *jsonValue     `json:"value,omitempty"`
+	Object   *jsonList      `json:"object,omitempty"`
+	Array    *jsonList      `json:"array,omitempty"`
+	Comments []*jsonComment `json:"comments,omitempty"`
+}
+
+type jsonList struct {
+	Span       *jsonSpan        `json:"span"`
+	Attributes []*jsonAttribute `json:"attributes"`
+}
+
+type jsonValue struct {
+	Span    *jsonSpan `json:"span"`
+	String  *string   `json:"string,omitempty"`
+	Number  *string   `json:"number,omitempty"`
+	Boolean *string   `json:"boolean,omitempty"`
+	Null    bool      `json:"null,omitempty"`
+}
+
+type jsonComment struct {
+	Span *jsonSpan `json:"span"`
+	Text string    `json:"text"`
+}
+
+// jsonSpan holds span with start and end locations, line and column are 1-based
+type jsonSpan struct {
+	Position int          `json:"position"`
+	Length   int          `json:"length"`
+	Start    jsonLocation `json:"start"`
+	End      jsonLocation `json:"end"`
+}
+
+type jsonLocation struct {
+	Offset int `json:"offset"`
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func newJSONAttribute(a *parser.Attribute) *jsonAttribute {
+	result := &jsonAttribute{
+		Name:   a.Name,
+		Quoted: a.Quoted,
+		Path:   a.Path,
+		Span:   newJSONSpan(a.Span),
+		Object: newJSONList(a.Object),
+		Array:  newJSONList(a.Array),
+	}
+	if a.Value != nil {
+		result.Value = &jsonValue{
+			Span:    newJSONSpan(a.Value.Span),
+			String:  a.Value.String,
+			Number:  a.Value.Number,
+			Boolean: a.Value.Boolean,
+			Null:    a.Value.Null,
+		}
+	}
+	for _, comment := range a.Comments {
+		result.Comments = append(result.Comments, &jsonComment{Span: newJSONSpan(comment.Span), Text: comment.Text})
+	}
+	return result
+}
+
+func newJSONList(a *parser.Attributes) *jsonList {
+	if a == nil {
+		return nil
+	}
+	result := &jsonList{Span: newJSONSpan(a.Span), Attributes: make([]*jsonAttribute, 0, len(a.Attributes))}
+	for _, attr := range a.Attributes {
+		result.Attributes = append(result.Attributes, newJSONAttribute(attr))
+	}
+	return result
+}
+
+func newJSONSpan(s *parser.SourceSpan) *jsonSpan {
+	if s == nil {
+		return nil
+	}
+	return &jsonSpan{
+		Position: s.Position,
+		Length:   s.Length,
+		Start:    jsonLocation{Offset: s.Start.Offset, Line: s.Start.Line, Column: s.Start.Column},
+		End:      jsonLocation{Offset: s.End.Offset, Line: s.End.Line, Column: s.End.Column},
+	}
+}
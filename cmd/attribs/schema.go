@@ -0,0 +1,179 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// loadJSONSchema loads subset of JSON Schema from file. Supported keywords are type (single type or list, "null"
+// makes value nullable), properties, required, additionalProperties, items, enum and local $ref to $defs or
+// definitions. Other keywords (title, description, ...) are ignored.
+func loadJSONSchema(filename string) (*schema, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	var root map[string]any
+	if err := json.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema %s: %w", filename, err)
+	}
+
+	l := &jsonSchemaLoader{root: root, refs: map[string]*schema{}}
+	result, err := l.load(root, "#")
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON Schema %s: %w", filename, err)
+	}
+	return result, nil
+}
+
+type jsonSchemaLoader struct {
+	root map[string]any
+	// refs holds already resolved references, so recursive schemas are supported
+	refs map[string]*schema
+}
+
+func (l *jsonSchemaLoader) load(raw any, path string) (*schema, error) {
+	switch raw := raw.(type) {
+	case bool:
+		// true allows anything, false nothing (represented as closed object without properties)
+		if raw {
+			return &schema{}, nil
+		}
+		return &schema{Types: []string{typeObject}, Closed: true}, nil
+	case map[string]any:
+		return l.loadObject(raw, path)
+	default:
+		return nil, fmt.Errorf("%s: schema must be object or boolean", path)
+	}
+}
+
+func (l *jsonSchemaLoader) loadObject(raw map[string]any, path string) (*schema, error) {
+	if ref, ok := raw["$ref"].(string); ok {
+		return l.resolve(ref)
+	}
+
+	result := &schema{}
+
+	switch typ := raw["type"].(type) {
+	case nil:
+	case string:
+		result.addType(typ)
+	case []any:
+		for _, item := range typ {
+			name, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/type: type must be string", path)
+			}
+			result.addType(name)
+		}
+	default:
+		return nil, fmt.Errorf("%s/type: type must be string or array of strings", path)
+	}
+	for _, typ := range result.Types {
+		switch typ {
+		case typeString, typeInteger, typeNumber, typeBoolean, typeObject, typeArray, typeNull:
+		default:
+			return nil, fmt.Errorf("%s/type: unsupported type %q", path, typ)
+		}
+	}
+
+	if enum, ok := raw["enum"]; ok {
+		values, ok := enum.([]any)
+		if !ok {
+			return nil, fmt.Errorf("%s/enum: enum must be array", path)
+		}
+		for _, value := range values {
+			switch value.(type) {
+			case nil:
+				result.Nullable = true
+			case string, bool, float64:
+				result.Enum = append(result.Enum, value)
+			default:
+				return nil, fmt.Errorf("%s/enum: only scalar values are supported", path)
+			}
+		}
+	}
+
+	if properties, ok := raw["properties"].(map[string]any); ok {
+		result.Properties = make(map[string]*schema, len(properties))
+		for name, property := range properties {
+			var err error
+			if result.Properties[name], err = l.load(property, path+"/properties/"+name); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if required, ok := raw["required"].([]any); ok {
+		for _, name := range required {
+			name, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("%s/required: property name must be string", path)
+			}
+			result.Required = append(result.Required, name)
+		}
+	}
+
+	switch additional := raw["additionalProperties"].(type) {
+	case nil:
+	case bool:
+		result.Closed = !additional
+	default:
+		var err error
+		if result.Additional, err = l.load(additional, path+"/additionalProperties"); err != nil {
+			return nil, err
+		}
+	}
+
+	if items, ok := raw["items"]; ok {
+		var err error
+		if result.Items, err = l.load(items, path+"/items"); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// resolve resolves local reference (#/$defs/name, #/definitions/name or # for root)
+func (l *jsonSchemaLoader) resolve(ref string) (*schema, error) {
+	if existing, ok := l.refs[ref]; ok {
+		return existing, nil
+	}
+	if !strings.HasPrefix(ref, "#") {
+		return nil, fmt.Errorf("unsupported $ref %q: only local references are supported", ref)
+	}
+
+	var raw any = l.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#"), "/")[1:] {
+		object, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("invalid $ref %q", ref)
+		}
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		if raw, ok = object[part]; !ok {
+			return nil, fmt.Errorf("invalid $ref %q: %s not found", ref, part)
+		}
+	}
+
+	// placeholder is registered first, so recursive references resolve to it
+	result := &schema{}
+	l.refs[ref] = result
+	loaded, err := l.load(raw, ref)
+	if err != nil {
+		return nil, err
+	}
+	*result = *loaded
+	return result, nil
+}
+
+// addType adds JSON Schema type, null makes schema nullable
+func (s *schema) addType(typ string) {
+	if typ == typeNull {
+		s.Nullable = true
+	}
+	s.Types = append(s.Types, typ)
+}
@@ -0,0 +1,59 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/phonkee/attribs"
+)
+
+type Level string
+
+type Config struct {
+	Name   string         `attr:"name=name"`
+	Port   uint16         `attr:"name=port"`
+	Level  Level          `attr:"name=level"`
+	Tags   []string       `attr:"name=tags"`
+	Labels map[string]int `attr:"name=labels"`
+	Child  *Config        `attr:"name=child"`
+	Path   string         `attr:"name=path, pos=0"`
+	Skip   string         `attr:"name=skip, disabled=true"`
+	Extra  any
+	Base
+}
+
+type Base struct {
+	ID int64 `attr:"name=id"`
+}
+
+type Invalid struct {
+	Name string `attr:"required"`
+}
+
+type NotStruct int
+
+type DTO struct {
+	MaxLength int    `json:"max_length,omitempty"`
+	Label     string `json:"label" attr:"name=title"`
+	Internal  string `json:"-"`
+	Plain     bool   `json:",omitempty"`
+}
+
+type HeaderDTO struct {
+	Type  string `json:"content-type"`
+	Dash  string `json:"-,"`
+	Value string `json:"value"`
+}
+
+type Amount big.Int
+
+type Numbers struct {
+	Ratio  float32          `attr:"name=ratio"`
+	Weight float64          `attr:"name=weight"`
+	Small  int8             `attr:"name=small"`
+	Count  *big.Int         `attr:"name=count"`
+	Total  big.Float        `attr:"name=total"`
+	Share  *big.Rat         `attr:"name=share"`
+	Raw    attribs.Number   `attr:"name=raw"`
+	Amount *Amount          `attr:"name=amount"`
+	Values []attribs.Number `attr:"name=values"`
+}
@@ -21,7 +21,8 @@ func Must[T any](t Definition[T], err error) Definition[T] {
 
 // New analyzes given struct and returns definition. definition can then parse tags and returns values
 // If something fails, this function panics
-func New[T any](what T) (result Definition[T], _ error) {
+// Optional options configure how Definition parses input.
+func New[T any](what T, options ...Options) (result Definition[T], _ error) {
 	// now we go over all fields and check which are used
 	typ := reflect.TypeOf(what)
 	isPtr := typ.Kind() == reflect.Ptr
@@ -36,24 +37,31 @@ func New[T any](what T) (result Definition[T], _ error) {
 		return result, ErrNotStruct
 	}
 
+	result = Definition[T]{
+		isPtr: isPtr,
+	}
+	if len(options) > 0 {
+		result.options = options[0]
+	}
+
 	//attr, err := inspect(*new(T), map[reflect.Type]*attr{})
-	attr, err := inspect(reflect.Indirect(reflect.New(typ)).Interface(), map[reflect.Type]*attr{})
+	in := &inspector{cache: map[reflect.Type]*attr{}, nameSource: result.options.NameSource}
+	attr, err := in.inspect(reflect.Indirect(reflect.New(typ)).Interface())
 
 	if err != nil {
 		return result, err
 	}
+	result.attr = attr
 
-	return Definition[T]{
-		attr:  attr,
-		isPtr: isPtr,
-	}, nil
+	return result, nil
 }
 
 // Definition defies definition of struct
 type Definition[T any] struct {
 	// structure attribute instance
-	attr  *attr
-	isPtr bool
+	attr    *attr
+	isPtr   bool
+	options Options
 }
 
 // Parse parses string with attributes into given type
@@ -62,16 +70,22 @@ func (d Definition[T]) Parse(input string, ignoreUnknown bool) (T, error) {
 	result := reflect.New(typ).Elem()
 
 	// parse input to attribute tree
-	attrs, err := parser.Parse(strings.NewReader(input))
+	attrs, err := parser.Parse(strings.NewReader(input), d.options.Parser)
 	if err != nil {
 		return result.Interface().(T), err
 	}
 
 	// create new value from given parsed attributes
-	err = d.attr.Set(result, attrs, ignoreUnknown)
+	err = d.attr.Set(result, attrs, setOptions{ignoreUnknown: ignoreUnknown, useNumber: d.options.UseNumber})
 	if err != nil {
 		return result.Interface().(T), err
 	}
 
 	return result.Interface().(T), nil
 }
+
+// Check parses input and returns only error, it's useful for tools that validate attribute strings (e.g. linters).
+func (d Definition[T]) Check(input string, ignoreUnknown bool) error {
+	_, err := d.Parse(input, ignoreUnknown)
+	return err
+}
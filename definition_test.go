@@ -1,9 +1,11 @@
 package attribs_test
 
 import (
+	"math"
 	"testing"
 
 	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/parser"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -520,6 +522,11 @@ func TestAttrs(t *testing.T) {
 			}{
 				{input: "", expected: Test{}},
 				{input: "metadata()", expected: Test{Metadata: map[string]string{}}},
+				{input: "metadata(a='x', b=y)", expected: Test{Metadata: map[string]string{"a": "x", "b": "y"}}},
+				{input: "metadata('Content-Type'='json', \"x.y\"=z, '1'=one, ''=empty)", expected: Test{
+					Metadata: map[string]string{"Content-Type": "json", "x.y": "z", "1": "one", "": "empty"},
+				}},
+				{input: "metadata_ptr('X-Id'='1')", expected: Test{MetadataPtr: ptr(map[string]string{"X-Id": "1"})}},
 			}
 
 			for _, item := range data {
@@ -533,6 +540,48 @@ func TestAttrs(t *testing.T) {
 			}
 		})
 
+		t.Run("test typed values", func(t *testing.T) {
+			type Header struct {
+				Value    string `attr:"name=value"`
+				Required bool   `attr:"name=required"`
+			}
+			type Test struct {
+				Counts  map[string]int      `attr:"name=counts"`
+				Headers map[string]*Header  `attr:"name=headers"`
+				Lists   map[string][]string `attr:"name=lists"`
+			}
+			def := attribs.Must(attribs.New(Test{}))
+
+			value, err := def.Parse("counts('a-b'=1, c=2), headers('Content-Type'(value=json, required)), lists('x.y'['a', 'b'])", false)
+			assert.NoError(t, err)
+			assert.Equal(t, Test{
+				Counts:  map[string]int{"a-b": 1, "c": 2},
+				Headers: map[string]*Header{"Content-Type": {Value: "json", Required: true}},
+				Lists:   map[string][]string{"x.y": {"a", "b"}},
+			}, value)
+
+			for _, item := range []struct {
+				input       string
+				errExpected string
+			}{
+				{input: "counts('a'='x')", errExpected: "cannot set map value for counts"},
+				{input: "counts(1)", errExpected: "expected key for map counts"},
+				{input: "headers('h'(unknown=1))", errExpected: "unknown attribute unknown"},
+				{input: "headers('h'('value'=1))", errExpected: `quoted key "value" is not allowed in struct h`},
+			} {
+				_, err := def.Parse(item.input, false)
+				assert.ErrorContains(t, err, item.errExpected, "input: %q", item.input)
+			}
+		})
+
+		t.Run("test quoted key in struct", func(t *testing.T) {
+			type Test struct {
+				Name string `attr:"name=name"`
+			}
+			def := attribs.Must(attribs.New(Test{}))
+			_, err := def.Parse("'name'='x'", true)
+			assert.ErrorContains(t, err, `quoted key "name" is not allowed in struct`)
+		})
 	})
 
 	t.Run("test any", func(t *testing.T) {
@@ -638,6 +687,292 @@ func TestAttrs(t *testing.T) {
 		}, got)
 	})
 
+	t.Run("test number literals", func(t *testing.T) {
+		type Numbers struct {
+			Int   int     `attr:"name=int"`
+			Int8  int8    `attr:"name=int8"`
+			U16   uint16  `attr:"name=u16"`
+			F32   float32 `attr:"name=f32"`
+			F64   float64 `attr:"name=f64"`
+			Any   any     `attr:"name=any"`
+			IntP  *int64  `attr:"name=int_p"`
+			UintP *uint8  `attr:"name=uint_p"`
+		}
+		def, err := attribs.New(Numbers{})
+		assert.NoError(t, err)
+
+		for _, item := range []struct {
+			input       string
+			expected    Numbers
+			errExpected string
+		}{
+			{input: "int=0xFF", expected: Numbers{Int: 255}},
+			{input: "int=0o755", expected: Numbers{Int: 0o755}},
+			{input: "int=0b1010", expected: Numbers{Int: 10}},
+			{input: "int=1_000_000", expected: Numbers{Int: 1000000}},
+			{input: "int=+5", expected: Numbers{Int: 5}},
+			{input: "int=-0x10", expected: Numbers{Int: -16}},
+			{input: "int=0755", expected: Numbers{Int: 755}},
+			{input: "int8=-128, u16=65535", expected: Numbers{Int8: -128, U16: 65535}},
+			{input: "int_p=0x7fff_ffff_ffff_ffff, uint_p=0b1111_1111", expected: Numbers{IntP: ptr(int64(0x7fffffffffffffff)), UintP: ptr(uint8(255))}},
+			{input: "f64=1e6", expected: Numbers{F64: 1e6}},
+			{input: "f64=2.5E-3", expected: Numbers{F64: 2.5e-3}},
+			{input: "f64=0x1p-2", expected: Numbers{F64: 0.25}},
+			{input: "f64=0xFF", expected: Numbers{F64: 255}},
+			{input: "f64=1_000.5", expected: Numbers{F64: 1000.5}},
+			{input: "f32=+1.5", expected: Numbers{F32: 1.5}},
+			{input: "f64=+Inf", expected: Numbers{F64: math.Inf(1)}},
+			{input: "f64=-Inf", expected: Numbers{F64: math.Inf(-1)}},
+			{input: "any=-Inf", expected: Numbers{Any: math.Inf(-1)}},
+			{input: "any=NaN", expected: Numbers{Any: "NaN"}},
+			{input: "any=0x10", expected: Numbers{Any: 16}},
+			{input: "any=1e3", expected: Numbers{Any: 1000.0}},
+			{input: "int8=300", errExpected: "value 300 out of range for int8"},
+			{input: "int8=-129", errExpected: "value -129 out of range for int8"},
+			{input: "u16=0x1_0000", errExpected: "value 0x1_0000 out of range for uint16"},
+			{input: "uint_p=256", errExpected: "value 256 out of range for uint8"},
+			{input: "f32=1e39", errExpected: "value 1e39 out of range for float32"},
+			{input: "int=1.5", errExpected: "invalid value for int"},
+			{input: "f64=Inf", errExpected: "invalid value for f64"},
+		} {
+			value, err := def.Parse(item.input, false)
+			if item.errExpected != "" {
+				assert.ErrorContains(t, err, item.errExpected, "input: %q", item.input)
+			} else {
+				assert.NoError(t, err, "input: %q", item.input)
+				assert.Equal(t, item.expected, value, "input: %q", item.input)
+			}
+		}
+
+		t.Run("NaN", func(t *testing.T) {
+			value, err := def.Parse("f64=+NaN", false)
+			assert.NoError(t, err)
+			assert.True(t, math.IsNaN(value.F64))
+		})
+
+		t.Run("bare special float is string", func(t *testing.T) {
+			type Options struct {
+				Mode string `attr:"name=mode"`
+				Name any    `attr:"name=name"`
+			}
+			value, err := attribs.Must(attribs.New(Options{})).Parse("mode=Inf, name=NaN", false)
+			assert.NoError(t, err)
+			assert.Equal(t, Options{Mode: "Inf", Name: "NaN"}, value)
+		})
+	})
+
+	t.Run("test number range", func(t *testing.T) {
+		type Port uint16
+		type Numbers struct {
+			Int8  int8      `attr:"name=int8"`
+			Int64 int64     `attr:"name=int64"`
+			Uint  uint      `attr:"name=uint"`
+			U8s   []uint8   `attr:"name=u8s"`
+			Port  *Port     `attr:"name=port"`
+			F32   float32   `attr:"name=f32"`
+			F64s  []float64 `attr:"name=f64s"`
+		}
+		def, err := attribs.New(Numbers{})
+		assert.NoError(t, err)
+
+		for _, item := range []struct {
+			input       string
+			expected    Numbers
+			errExpected string
+			errPosition int
+		}{
+			{input: "int8=127, int64=-9223372036854775808, uint=0, u8s[0, 255], port=65535", expected: Numbers{
+				Int8: 127, Int64: -9223372036854775808, U8s: []uint8{0, 255}, Port: ptr(Port(65535)),
+			}},
+			{input: "f32=3.4e38, f64s[1e308, -Inf]", expected: Numbers{F32: 3.4e38, F64s: []float64{1e308, math.Inf(-1)}}},
+			{input: "int8=300", errExpected: "value 300 out of range for int8: -128..127", errPosition: 5},
+			{input: "int64=9223372036854775808", errExpected: "value 9223372036854775808 out of range for int64: -9223372036854775808..9223372036854775807", errPosition: 6},
+			{input: "uint=-1", errExpected: "value -1 out of range for uint: 0..18446744073709551615", errPosition: 5},
+			{input: "uint=-0x10", errExpected: "value -0x10 out of range for uint", errPosition: 5},
+			{input: "u8s[1, 256]", errExpected: "value 256 out of range for uint8: 0..255", errPosition: 7},
+			{input: "port=70000", errExpected: "value 70000 out of range for attribs_test.Port (uint16): 0..65535", errPosition: 5},
+			{input: "f32=1e39", errExpected: "value 1e39 out of range for float32: ±3.4028234663852886e+38", errPosition: 4},
+			{input: "f64s[1e309]", errExpected: "value 1e309 out of range for float64: ±1.7976931348623157e+308", errPosition: 5},
+			{input: "uint=abc", errExpected: "invalid value for uint", errPosition: 0},
+		} {
+			value, err := def.Parse(item.input, false)
+			if item.errExpected != "" {
+				assert.ErrorContains(t, err, item.errExpected, "input: %q", item.input)
+				var pe parser.ParseError
+				if assert.ErrorAs(t, err, &pe, "input: %q", item.input) {
+					assert.Equal(t, item.errPosition, pe.Position(), "input: %q", item.input)
+				}
+			} else {
+				assert.NoError(t, err, "input: %q", item.input)
+				assert.Equal(t, item.expected, value, "input: %q", item.input)
+			}
+		}
+	})
+
+	t.Run("test error location", func(t *testing.T) {
+		type Config struct {
+			Name  string `attr:"name=name"`
+			Count int8   `attr:"name=count"`
+		}
+		def := attribs.Must(attribs.New(Config{}))
+		for _, item := range []struct {
+			input  string
+			line   int
+			column int
+		}{
+			{input: "name='čšť',\ncount=300", line: 2, column: 7},
+			{input: "name='x',\n\n  count=", line: 3, column: 9},
+		} {
+			_, err := def.Parse(item.input, false)
+			var pe parser.ParseError
+			if assert.ErrorAs(t, err, &pe, "input: %q", item.input) {
+				assert.Equal(t, item.line, pe.Line(), "input: %q", item.input)
+				assert.Equal(t, item.column, pe.Column(), "input: %q", item.input)
+			}
+		}
+	})
+
+	t.Run("test comments", func(t *testing.T) {
+		type Config struct {
+			Name  string `attr:"name=name"`
+			Ports []int  `attr:"name=ports"`
+		}
+		input := `
+			# service name
+			name='api',
+			ports[
+				80, // http
+				443 /* https */
+			]`
+
+		strict := attribs.Options{Parser: parser.Options{RejectStrayCharacters: true}}
+		_, err := attribs.Must(attribs.New(Config{}, strict)).Parse(input, false)
+		assert.ErrorContains(t, err, "unexpected character '#'")
+
+		def := attribs.Must(attribs.New(Config{}, attribs.Options{Parser: parser.Options{Comments: true}}))
+		value, err := def.Parse(input, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Config{Name: "api", Ports: []int{80, 443}}, value)
+
+		_, err = def.Parse("name='api' /* unterminated", false)
+		assert.ErrorContains(t, err, "unterminated block comment")
+	})
+
+	t.Run("test null", func(t *testing.T) {
+		type Inner struct {
+			ID int `attr:"name=id"`
+		}
+		type Nullable struct {
+			Name   *string        `attr:"name=name"`
+			Count  int            `attr:"name=count"`
+			Inner  *Inner         `attr:"name=inner"`
+			Tags   []string       `attr:"name=tags"`
+			Meta   map[string]any `attr:"name=meta"`
+			Any    any            `attr:"name=any"`
+			IDs    []*int         `attr:"name=ids"`
+			Counts []int          `attr:"name=counts"`
+		}
+		def := attribs.Must(attribs.New(Nullable{}))
+
+		value, err := def.Parse("name=null, inner=nil, tags=null, meta=null, any=null, ids[1, null], meta(a=null)", false)
+		assert.NoError(t, err)
+		assert.Equal(t, Nullable{IDs: []*int{ptr(1), nil}, Meta: map[string]any{"a": nil}}, value)
+
+		value, err = def.Parse("name='null'", false)
+		assert.NoError(t, err)
+		assert.Equal(t, Nullable{Name: ptr("null")}, value)
+
+		for _, item := range []struct {
+			input       string
+			errExpected string
+			errPosition int
+		}{
+			{input: "count=null", errExpected: "cannot set null to count: int is not nullable", errPosition: 6},
+			{input: "counts[1, nil]", errExpected: "cannot set null: int is not nullable", errPosition: 10},
+		} {
+			_, err := def.Parse(item.input, false)
+			assert.ErrorContains(t, err, item.errExpected, "input: %q", item.input)
+			var pe parser.ParseError
+			if assert.ErrorAs(t, err, &pe, "input: %q", item.input) {
+				assert.Equal(t, item.errPosition, pe.Position(), "input: %q", item.input)
+			}
+		}
+	})
+
+	t.Run("test multi-line strings", func(t *testing.T) {
+		type Query struct {
+			Name string `attr:"name=name"`
+			SQL  string `attr:"name=sql"`
+		}
+		input := "name=users, sql=\"\"\"\n\t\tSELECT *\n\t\tFROM users\n\t\t  WHERE id = ?\n\t\"\"\""
+
+		value, err := attribs.Must(attribs.New(Query{})).Parse(input, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Query{Name: "users", SQL: "\n\t\tSELECT *\n\t\tFROM users\n\t\t  WHERE id = ?\n\t"}, value)
+
+		def := attribs.Must(attribs.New(Query{}, attribs.Options{Parser: parser.Options{DedentStrings: true}}))
+		value, err = def.Parse(input, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Query{Name: "users", SQL: "SELECT *\nFROM users\n  WHERE id = ?\n"}, value)
+	})
+
+	t.Run("test dotted paths", func(t *testing.T) {
+		type Pool struct {
+			Max int `attr:"name=max"`
+			Min int `attr:"name=min"`
+		}
+		type DB struct {
+			Name   string            `attr:"name=name"`
+			Pool   *Pool             `attr:"name=pool"`
+			Labels map[string]string `attr:"name=labels"`
+		}
+		type Config struct {
+			DB   DB             `attr:"name=db"`
+			Meta map[string]any `attr:"name=meta"`
+		}
+		def := attribs.Must(attribs.New(Config{}))
+
+		value, err := def.Parse("db.pool.max=10, db.name='main', db.pool.min=1, db.labels.env=prod, meta.a.b=1", false)
+		assert.NoError(t, err)
+		assert.Equal(t, Config{
+			DB: DB{
+				Name:   "main",
+				Pool:   &Pool{Max: 10, Min: 1},
+				Labels: map[string]string{"env": "prod"},
+			},
+			Meta: map[string]any{"a": map[string]any{"b": 1}},
+		}, value)
+
+		value, err = def.Parse("db(pool.max=10)", false)
+		assert.NoError(t, err)
+		assert.Equal(t, Config{DB: DB{Pool: &Pool{Max: 10}}}, value)
+
+		for _, item := range []struct {
+			input       string
+			errExpected string
+			position    int
+			length      int
+			conflict    int
+			conflictLen int
+		}{
+			{input: "db(name='x'), db.pool.max=10", errExpected: "path db.pool.max conflicts with attribute db", position: 14, length: 11, conflict: 0, conflictLen: 2},
+			{input: "db.pool=1, db.pool.max=10", errExpected: "path pool.max conflicts with attribute pool", position: 11, length: 11, conflict: 0, conflictLen: 7},
+		} {
+			_, err := def.Parse(item.input, false)
+			assert.ErrorContains(t, err, item.errExpected, "input: %q", item.input)
+			var ce parser.ConflictError
+			if assert.ErrorAs(t, err, &ce, "input: %q", item.input) {
+				assert.Equal(t, item.position, ce.Position(), "input: %q", item.input)
+				assert.Equal(t, item.length, ce.Span().Length, "input: %q", item.input)
+				assert.Equal(t, item.conflict, ce.ConflictPosition(), "input: %q", item.input)
+				assert.Equal(t, item.conflictLen, ce.ConflictSpan().Length, "input: %q", item.input)
+			}
+		}
+
+		_, err = def.Parse("db.unknown.x=1", false)
+		assert.ErrorContains(t, err, "unknown attribute unknown")
+	})
+
 	t.Run("test known", func(t *testing.T) {
 		type Struct struct {
 			ID int `attr:"name=id"`
@@ -692,3 +1027,256 @@ func TestAttrsMust(t *testing.T) {
 	})
 
 }
+
+func TestNameSource(t *testing.T) {
+	type Limits struct {
+		Min int `yaml:"min"`
+	}
+	type FieldDTO struct {
+		MaxLength int            `json:"max_length,omitempty" yaml:"maxLength"`
+		Label     string         `json:"label" attr:"name=title"`
+		Internal  chan int       `json:"-" yaml:"-"`
+		Plain     bool           `json:",omitempty"`
+		Limits    Limits         `json:"limits"`
+		Extra     map[string]int `json:"extra,omitempty"`
+	}
+
+	t.Run("test json", func(t *testing.T) {
+		d, err := attribs.New(FieldDTO{}, attribs.Options{NameSource: "json"})
+		assert.NoError(t, err)
+
+		value, err := d.Parse("max_length=10, title=x, Plain, limits(Min=1), extra(a=1)", false)
+		assert.NoError(t, err)
+		assert.Equal(t, FieldDTO{MaxLength: 10, Label: "x", Plain: true, Limits: Limits{Min: 1}, Extra: map[string]int{"a": 1}}, value)
+
+		for _, input := range []string{"MaxLength=1", "label=x", "Internal=1"} {
+			_, err = d.Parse(input, false)
+			assert.Error(t, err, "input: %q", input)
+		}
+	})
+
+	t.Run("test yaml", func(t *testing.T) {
+		d, err := attribs.New(FieldDTO{}, attribs.Options{NameSource: "yaml"})
+		assert.NoError(t, err)
+
+		value, err := d.Parse("maxLength=10, Limits(min=1), Plain", false)
+		assert.NoError(t, err)
+		assert.Equal(t, FieldDTO{MaxLength: 10, Plain: true, Limits: Limits{Min: 1}}, value)
+	})
+
+	t.Run("test without name source", func(t *testing.T) {
+		// channel field is not skipped without name source
+		_, err := attribs.New(FieldDTO{})
+		assert.ErrorIs(t, err, attribs.ErrUnsupportedType)
+	})
+
+	t.Run("test invalid name", func(t *testing.T) {
+		type HeaderDTO struct {
+			Type  string `json:"content-type"`
+			Dash  string `json:"-,"`
+			Value string `json:"value"`
+		}
+		d := attribs.Must(attribs.New(HeaderDTO{}, attribs.Options{NameSource: "json"}))
+
+		value, err := d.Parse("value=x", false)
+		assert.NoError(t, err)
+		assert.Equal(t, HeaderDTO{Value: "x"}, value)
+
+		for _, input := range []string{"Type=x", "Dash=x", "'content-type'=x"} {
+			_, err := d.Parse(input, false)
+			assert.Error(t, err, "input: %q", input)
+		}
+	})
+
+	t.Run("test unmarshal and marshal", func(t *testing.T) {
+		options := attribs.Options{NameSource: "json"}
+		var value FieldDTO
+		assert.NoError(t, attribs.Unmarshal("max_length=3, limits(Min=2)", &value, options))
+		assert.Equal(t, FieldDTO{MaxLength: 3, Limits: Limits{Min: 2}}, value)
+
+		// same type is cached separately for each name source
+		assert.Error(t, attribs.Unmarshal("max_length=3", &struct {
+			MaxLength int `json:"max_length"`
+		}{}))
+
+		value.Internal = nil
+		out, err := attribs.Marshal(value, options)
+		assert.NoError(t, err)
+		assert.Equal(t, "max_length=3, title='', Plain=false, limits(Min=2)", out)
+	})
+}
+
+func TestSyntax(t *testing.T) {
+	type Column struct {
+		Name     string   `attr:"name=name"`
+		Size     int      `attr:"name=size"`
+		Ratio    float64  `attr:"name=ratio"`
+		Label    string   `attr:"name=label"`
+		Required bool     `attr:"name=required"`
+		Default  *string  `attr:"name=default"`
+		Tags     []string `attr:"name=tags"`
+	}
+
+	t.Run("test struct tag", func(t *testing.T) {
+		d, err := attribs.New(Column{}, attribs.Options{Parser: parser.Options{Syntax: parser.StructTagSyntax{}}})
+		assert.NoError(t, err)
+
+		value, err := d.Parse(`name:"user_id" size:"255" ratio:"0.5" label:"255" required:"true" default:"x"`, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Column{Name: "user_id", Size: 255, Ratio: 0.5, Label: "255", Required: true, Default: ptr("x")}, value)
+
+		_, err = d.Parse(`size:"big"`, false)
+		assert.Error(t, err)
+	})
+
+	t.Run("test semicolon", func(t *testing.T) {
+		d, err := attribs.New(Column{}, attribs.Options{Parser: parser.Options{Syntax: parser.SemicolonSyntax{Separator: ':'}}})
+		assert.NoError(t, err)
+
+		value, err := d.Parse(`name:user id; size:10; label:'a; b'; required`, false)
+		assert.NoError(t, err)
+		assert.Equal(t, Column{Name: "user id", Size: 10, Label: "a; b", Required: true}, value)
+
+		_, err = d.Parse(`tags:a`, false)
+		assert.Error(t, err)
+	})
+}
+
+func TestUnknownAttributeSuggestion(t *testing.T) {
+	type Limits struct {
+		Min int `attr:"name=min"`
+		Max int `attr:"name=max"`
+	}
+	type Base struct {
+		Debug bool `attr:"name=debug"`
+	}
+	type Field struct {
+		Base
+		MaxLength int    `attr:"name=max_length"`
+		Label     string `attr:"name=title"`
+		Limits    Limits `attr:"name=limits"`
+	}
+	d := attribs.Must(attribs.New(Field{}))
+
+	for _, item := range []struct {
+		input      string
+		suggestion string
+		position   int
+	}{
+		{input: "maxlength=1", suggestion: "max_length"},
+		{input: "title=x, max_lenght=1", suggestion: "max_length", position: 9},
+		{input: "Label=x", suggestion: "title"},
+		{input: "debg", suggestion: "debug"},
+		{input: "limits(mim=1)", suggestion: "min", position: 7},
+		{input: "Bse(debug)", suggestion: "Base"},
+		{input: "unknown=1", suggestion: ""},
+	} {
+		t.Run(item.input, func(t *testing.T) {
+			_, err := d.Parse(item.input, false)
+			var ue parser.UnknownAttributeError
+			if assert.ErrorAs(t, err, &ue) {
+				assert.Equal(t, item.suggestion, ue.Suggestion())
+				assert.Equal(t, item.position, ue.Position())
+				if item.suggestion != "" {
+					assert.ErrorContains(t, err, "did you mean "+item.suggestion+"?")
+				}
+			}
+		})
+	}
+
+	t.Run("test attribute name wins over field name", func(t *testing.T) {
+		type Swapped struct {
+			Title string `attr:"name=name"`
+			Name  string `attr:"name=label"`
+		}
+		_, err := attribs.Must(attribs.New(Swapped{})).Parse("NAME=x", false)
+		var ue parser.UnknownAttributeError
+		if assert.ErrorAs(t, err, &ue) {
+			assert.Equal(t, "name", ue.Suggestion())
+		}
+	})
+
+	t.Run("test case colliding candidates", func(t *testing.T) {
+		type Colliding struct {
+			Lower string `attr:"name=id"`
+			Upper string `attr:"name=Id"`
+			Id    string `attr:"name=first"`
+			ID    string `attr:"name=second"`
+		}
+		d := attribs.Must(attribs.New(Colliding{}))
+		for _, item := range []struct {
+			input      string
+			suggestion string
+		}{
+			{input: "ID=x", suggestion: "Id"},
+			{input: "iD=x", suggestion: "Id"},
+			{input: "ID(x)", suggestion: "Id"},
+		} {
+			// properties are map, suggestion must not depend on its iteration order
+			for i := 0; i < 20; i++ {
+				_, err := d.Parse(item.input, false)
+				var ue parser.UnknownAttributeError
+				if assert.ErrorAs(t, err, &ue, "input: %q", item.input) {
+					assert.Equal(t, item.suggestion, ue.Suggestion(), "input: %q", item.input)
+				}
+			}
+		}
+
+		type Fields struct {
+			Id string `attr:"name=first"`
+			ID string `attr:"name=second"`
+		}
+		fd := attribs.Must(attribs.New(Fields{}))
+		for i := 0; i < 20; i++ {
+			_, err := fd.Parse("id=x", false)
+			var ue parser.UnknownAttributeError
+			if assert.ErrorAs(t, err, &ue) {
+				assert.Equal(t, "first", ue.Suggestion())
+			}
+		}
+	})
+}
+
+func TestPointerFields(t *testing.T) {
+	type Inner struct {
+		Value int `attr:"name=value"`
+	}
+	type Struct struct {
+		Int    *int              `attr:"name=int"`
+		Uint   *uint8            `attr:"name=uint"`
+		Float  *float64          `attr:"name=float"`
+		Bool   *bool             `attr:"name=bool"`
+		String *string           `attr:"name=string"`
+		Slice  *[]string         `attr:"name=slice"`
+		Map    *map[string]int   `attr:"name=map"`
+		Inner  *Inner            `attr:"name=inner"`
+		Items  []*int            `attr:"name=items"`
+		Values map[string]*Inner `attr:"name=values"`
+	}
+	d := attribs.Must(attribs.New(Struct{}))
+
+	for _, item := range []struct {
+		input    string
+		expected Struct
+	}{
+		{input: "int=-1", expected: Struct{Int: ptr(-1)}},
+		{input: "uint=255", expected: Struct{Uint: ptr[uint8](255)}},
+		{input: "float=1.5", expected: Struct{Float: ptr(1.5)}},
+		{input: "bool", expected: Struct{Bool: ptr(true)}},
+		{input: "string='ž'", expected: Struct{String: ptr("ž")}},
+		{input: "slice[a, b]", expected: Struct{Slice: ptr([]string{"a", "b"})}},
+		{input: "map(a=1)", expected: Struct{Map: ptr(map[string]int{"a": 1})}},
+		{input: "inner(value=1)", expected: Struct{Inner: &Inner{Value: 1}}},
+		{input: "items[1, 2]", expected: Struct{Items: []*int{ptr(1), ptr(2)}}},
+		{input: "values(a(value=1))", expected: Struct{Values: map[string]*Inner{"a": {Value: 1}}}},
+	} {
+		t.Run(item.input, func(t *testing.T) {
+			value, err := d.Parse(item.input, false)
+			assert.NoError(t, err)
+			assert.Equal(t, item.expected, value)
+		})
+	}
+
+	_, err := d.Parse("uint=256", false)
+	assert.ErrorContains(t, err, "out of range for uint8")
+}
@@ -8,4 +8,5 @@ var (
 	ErrDuplicateField  = errors.New("duplicate field")
 	ErrMapKeyNotStr    = errors.New("map key is not a string")
 	ErrUnsupportedType = errors.New("unsupported type")
+	ErrInvalidTarget   = errors.New("invalid target")
 )
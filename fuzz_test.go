@@ -0,0 +1,63 @@
+package attribs_test
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/parser"
+)
+
+// fuzzTag is representative struct covering all supported kinds
+type fuzzTag struct {
+	Name     string         `attr:"name=name,pos=0"`
+	Count    int8           `attr:"name=count"`
+	Size     *uint          `attr:"name=size"`
+	Ratio    float32        `attr:"name=ratio"`
+	Enabled  *bool          `attr:"name=enabled"`
+	Label    *string        `attr:"name=label"`
+	Span     *Interval      `attr:"name=span"`
+	Tags     []string       `attr:"name=tags"`
+	Matrix   [][]int        `attr:"name=matrix"`
+	Meta     map[string]any `attr:"name=meta"`
+	Any      any            `attr:"name=any"`
+	Children []*fuzzTag     `attr:"name=children"`
+}
+
+func FuzzDefinitionParse(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"'first', count=1, size=2, ratio=0.5, enabled, label='x'",
+		"span(start=1, end=2)",
+		"tags['a', 'b'], matrix[[1, 2], [3]]",
+		"meta(hello='world', priority=42, nested(a=1)), any=3.14",
+		"children[(name=a, children[(name=b)])]",
+		"enabled=false, size=-1",
+		"count=300",
+		"unknown=1",
+		"span=1",
+		"tags=1",
+		"meta=1",
+		"any[1, 'a', (b=2)]",
+		"name='user_id', span(start=0, end=255), tags['id', 'primary']",
+	} {
+		f.Add(seed, false)
+		f.Add(seed, true)
+	}
+
+	def := attribs.Must(attribs.New(fuzzTag{}))
+
+	f.Fuzz(func(t *testing.T, input string, ignoreUnknown bool) {
+		_, err := def.Parse(input, ignoreUnknown)
+		if err == nil {
+			return
+		}
+		var pe parser.ParseError
+		if errors.As(err, &pe) {
+			if pe.Position() < 0 || pe.Position() > utf8.RuneCountInString(input) {
+				t.Fatalf("Parse(%q): error position %d out of input bounds: %v", input, pe.Position(), err)
+			}
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package attribs
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+var (
+	// attrs caches inspected attributes by type for Unmarshal
+	attrs sync.Map
+
+	// fields caches marshalled fields of struct types for Marshal
+	fields sync.Map
+)
+
+// Unmarshal parses input and stores result in value pointed to by v, in the style of json.Unmarshal. v can point to
+// struct, map with string keys or any. Structs and maps are inspected once per type (same as New) and cached.
+// Numbers in any values are int or float64 unless Options.UseNumber is set.
+func Unmarshal(input string, v any, options ...Options) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Pointer || target.IsNil() {
+		return fmt.Errorf("%w: expected non-nil pointer, got %T", ErrInvalidTarget, v)
+	}
+	target = target.Elem()
+
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	parsed, err := parser.Parse(strings.NewReader(input), opts.Parser)
+	if err != nil {
+		return err
+	}
+	setOpts := setOptions{useNumber: opts.UseNumber}
+
+	// any target gets maps and slices same as any fields
+	if target.Kind() == reflect.Interface {
+		value, err := buildAny(parsed, setOpts)
+		if err != nil {
+			return err
+		}
+		target.Set(value)
+		return nil
+	}
+
+	// pointer to pointer to struct, Set allocates the last one
+	for target.Kind() == reflect.Pointer && target.Type().Elem().Kind() == reflect.Pointer {
+		if target.IsNil() {
+			target.Set(reflect.New(target.Type().Elem()))
+		}
+		target = target.Elem()
+	}
+
+	a, err := cachedAttr(target.Type(), opts.NameSource)
+	if err != nil {
+		return err
+	}
+	return a.Set(target, parsed, setOpts)
+}
+
+// cacheKey is key of cached attributes and fields, names depend on name source
+type cacheKey struct {
+	typ        reflect.Type
+	nameSource string
+}
+
+// cachedAttr returns inspected attribute for struct or map type (or pointer to them)
+func cachedAttr(typ reflect.Type, nameSource string) (*attr, error) {
+	key := cacheKey{typ: typ, nameSource: nameSource}
+	if cached, ok := attrs.Load(key); ok {
+		return cached.(*attr), nil
+	}
+
+	base := typ
+	if base.Kind() == reflect.Pointer {
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct && base.Kind() != reflect.Map {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidTarget, typ)
+	}
+
+	in := &inspector{cache: map[reflect.Type]*attr{}, nameSource: nameSource}
+	result, err := in.inspect(reflect.Indirect(reflect.New(base)).Interface())
+	if err != nil {
+		return nil, err
+	}
+	cached, _ := attrs.LoadOrStore(key, result)
+	return cached.(*attr), nil
+}
+
+// Marshal returns attribute string of v, it's inverse of Unmarshal. v can be struct, map with string keys or
+// pointer to them. Struct fields are named by their attr tags, positional fields come first and nil fields are
+// omitted. Map keys are sorted. Options.NameSource is used for names same as in New.
+func Marshal(v any, options ...Options) (string, error) {
+	var opts Options
+	if len(options) > 0 {
+		opts = options[0]
+	}
+
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return "", nil
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct && value.Kind() != reflect.Map {
+		return "", fmt.Errorf("%w: %T", ErrUnsupportedType, v)
+	}
+
+	result, err := marshalValue(value, opts.NameSource)
+	if err != nil {
+		return "", err
+	}
+	return parser.Print(result, parser.PrintOptions{}), nil
+}
+
+// marshalField is struct field (or field of embedded struct) with its attribute name
+type marshalField struct {
+	index      []int
+	name       string
+	position   int
+	positional bool
+}
+
+// structFields returns fields of struct type in order they are marshalled (positional by position, then named
+// in declaration order), it uses the same tag rules as inspect.
+func structFields(typ reflect.Type, nameSource string) ([]marshalField, error) {
+	key := cacheKey{typ: typ, nameSource: nameSource}
+	if cached, ok := fields.Load(key); ok {
+		return cached.([]marshalField), nil
+	}
+
+	var (
+		result []marshalField
+		walk   func(typ reflect.Type, index []int) error
+	)
+	walk = func(typ reflect.Type, index []int) error {
+		for i := 0; i < typ.NumField(); i++ {
+			field := typ.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			pa, err := fieldAttribs(field, nameSource)
+			if err != nil {
+				return err
+			}
+			if pa.Disabled {
+				continue
+			}
+
+			fieldIndex := append(append([]int{}, index...), i)
+			// fields of embedded structs are promoted
+			if embedded := field.Type; field.Anonymous && pa.Alias == "" {
+				if embedded.Kind() == reflect.Pointer {
+					embedded = embedded.Elem()
+				}
+				if embedded.Kind() == reflect.Struct {
+					if err := walk(embedded, fieldIndex); err != nil {
+						return err
+					}
+					continue
+				}
+			}
+
+			name := pa.Alias
+			if name == "" {
+				name = field.Name
+			}
+			result = append(result, marshalField{index: fieldIndex, name: name, position: pa.Position, positional: pa.IsPositional})
+		}
+		return nil
+	}
+	if err := walk(typ, nil); err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		if result[i].positional != result[j].positional {
+			return result[i].positional
+		}
+		return result[i].positional && result[i].position < result[j].position
+	})
+
+	cached, _ := fields.LoadOrStore(key, result)
+	return cached.([]marshalField), nil
+}
+
+// marshalValue returns unnamed attribute for value
+func marshalValue(value reflect.Value, nameSource string) (*parser.Attribute, error) {
+	for value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return &parser.Attribute{Value: &parser.Value{Null: true}}, nil
+		}
+		value = value.Elem()
+	}
+
+	if value.Type() == numberType {
+		number := value.String()
+		if number == "" {
+			number = "0"
+		}
+		return &parser.Attribute{Value: &parser.Value{Number: &number}}, nil
+	}
+
+	switch value.Type() {
+	case bigIntType, bigFloatType, bigRatType:
+		number, err := formatBig(value)
+		if err != nil {
+			return nil, err
+		}
+		return &parser.Attribute{Value: &parser.Value{Number: &number}}, nil
+	}
+
+	switch value.Kind() {
+	case reflect.Bool:
+		b := strconv.FormatBool(value.Bool())
+		return &parser.Attribute{Value: &parser.Value{Boolean: &b, String: &b}}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		number := strconv.FormatInt(value.Int(), 10)
+		return &parser.Attribute{Value: &parser.Value{Number: &number}}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		number := strconv.FormatUint(value.Uint(), 10)
+		return &parser.Attribute{Value: &parser.Value{Number: &number}}, nil
+	case reflect.Float32, reflect.Float64:
+		number := formatFloat(value.Float(), value.Type().Bits())
+		return &parser.Attribute{Value: &parser.Value{Number: &number}}, nil
+	case reflect.String:
+		s := value.String()
+		return &parser.Attribute{Value: &parser.Value{String: &s}}, nil
+	case reflect.Slice, reflect.Array:
+		if value.Kind() == reflect.Slice && value.IsNil() {
+			return &parser.Attribute{Value: &parser.Value{Null: true}}, nil
+		}
+		result := &parser.Attribute{Array: &parser.Attributes{}}
+		for i := 0; i < value.Len(); i++ {
+			item, err := marshalValue(value.Index(i), nameSource)
+			if err != nil {
+				return nil, err
+			}
+			result.Array.Push(item)
+		}
+		return result, nil
+	case reflect.Map:
+		if value.Type().Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("%w: %s", ErrMapKeyNotStr, value.Type().Key())
+		}
+		if value.IsNil() {
+			return &parser.Attribute{Value: &parser.Value{Null: true}}, nil
+		}
+		keys := value.MapKeys()
+		sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+		result := &parser.Attribute{Object: &parser.Attributes{}}
+		for _, key := range keys {
+			item, err := marshalValue(value.MapIndex(key), nameSource)
+			if err != nil {
+				return nil, err
+			}
+			item.Name = key.String()
+			// keys which are not identifiers (Content-Type, db.host) are quoted
+			item.Quoted = parser.ValidateIdentifier(item.Name) != nil
+			result.Object.Push(item)
+		}
+		return result, nil
+	case reflect.Struct:
+		structFields, err := structFields(value.Type(), nameSource)
+		if err != nil {
+			return nil, err
+		}
+		result := &parser.Attribute{Object: &parser.Attributes{}}
+		for _, field := range structFields {
+			fieldValue, err := value.FieldByIndexErr(field.index)
+			if err != nil {
+				// nil embedded struct pointer
+				continue
+			}
+			if isNil(fieldValue) {
+				continue
+			}
+			item, err := marshalValue(fieldValue, nameSource)
+			if err != nil {
+				return nil, err
+			}
+			if !field.positional {
+				item.Name = field.name
+			}
+			result.Object.Push(item)
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type())
+}
+
+// isNil returns whether value is nil pointer, interface, map or slice
+func isNil(value reflect.Value) bool {
+	switch value.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		return value.IsNil()
+	}
+	return false
+}
+
+// formatBig formats big.Int, big.Float or big.Rat as number literal
+func formatBig(value reflect.Value) (string, error) {
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+
+	switch number := value.Addr().Interface().(type) {
+	case *big.Int:
+		return number.String(), nil
+	case *big.Float:
+		if number.IsInf() {
+			return formatFloat(math.Inf(number.Sign()), 64), nil
+		}
+		return number.Text('g', -1), nil
+	case *big.Rat:
+		if number.IsInt() {
+			return number.Num().String(), nil
+		}
+		// only rationals with finite decimal expansion have number literal (1/3 has not)
+		prec, exact := number.FloatPrec()
+		if !exact {
+			return "", fmt.Errorf("%w: %s has no exact number literal", ErrUnsupportedType, number.RatString())
+		}
+		return number.FloatString(prec), nil
+	}
+	return "", fmt.Errorf("%w: %s", ErrUnsupportedType, value.Type())
+}
+
+// formatFloat formats float as shortest number literal that parses back to the same value
+func formatFloat(f float64, bits int) string {
+	// bare Inf and NaN are identifiers, special values are numbers only with sign
+	switch {
+	case math.IsInf(f, 1):
+		return "+Inf"
+	case math.IsInf(f, -1):
+		return "-Inf"
+	case math.IsNaN(f):
+		return "+NaN"
+	}
+	result := strconv.FormatFloat(f, 'g', -1, bits)
+	// keep floats floats, so any values built from output are float64 again
+	if !strings.ContainsAny(result, ".eIN") {
+		result += ".0"
+	}
+	return result
+}
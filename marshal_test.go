@@ -0,0 +1,249 @@
+package attribs_test
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/parser"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type MarshalMixin struct {
+	Label string `attr:"name=label"`
+}
+
+type MarshalDef struct {
+	MarshalMixin
+	Name     string            `attr:"name=name,pos=0"`
+	Kind     string            `attr:"name=kind,pos=1"`
+	ID       int               `attr:"name=id"`
+	Ratio    float64           `attr:"name=ratio"`
+	Enabled  bool              `attr:"name=enabled"`
+	Optional *uint8            `attr:"name=optional"`
+	Span     *Interval         `attr:"name=span"`
+	Tags     []string          `attr:"name=tags"`
+	Headers  map[string]string `attr:"name=headers"`
+	Extra    any               `attr:"name=extra"`
+	Big      attribs.Number    `attr:"name=big"`
+	Hidden   string            `attr:"name=hidden,disabled=true"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Run("test struct", func(t *testing.T) {
+		var def MarshalDef
+		err := attribs.Unmarshal("'user', 'admin', id=1, label=x, span(start=1, end=2), tags['a', 'b'], big=123456789012345678901234567890", &def)
+		require.NoError(t, err)
+		assert.Equal(t, MarshalDef{
+			MarshalMixin: MarshalMixin{Label: "x"},
+			Name:         "user",
+			Kind:         "admin",
+			ID:           1,
+			Span:         &Interval{Start: 1, End: 2},
+			Tags:         []string{"a", "b"},
+			Big:          "123456789012345678901234567890",
+		}, def)
+	})
+
+	t.Run("test keeps existing values", func(t *testing.T) {
+		def := Interval{Start: 1, End: 2}
+		require.NoError(t, attribs.Unmarshal("end=3", &def))
+		assert.Equal(t, Interval{Start: 1, End: 3}, def)
+	})
+
+	t.Run("test pointer to pointer", func(t *testing.T) {
+		var def *Interval
+		require.NoError(t, attribs.Unmarshal("start=1", &def))
+		assert.Equal(t, &Interval{Start: 1}, def)
+	})
+
+	t.Run("test map", func(t *testing.T) {
+		var m map[string]int
+		require.NoError(t, attribs.Unmarshal("a=1, 'b-c'=2", &m))
+		assert.Equal(t, map[string]int{"a": 1, "b-c": 2}, m)
+	})
+
+	t.Run("test any", func(t *testing.T) {
+		var v any
+		require.NoError(t, attribs.Unmarshal("id=1, ratio=0.5, ok, tags['a', 2], db.port=5432, none=null", &v))
+		assert.Equal(t, map[string]any{
+			"id":    1,
+			"ratio": 0.5,
+			"ok":    true,
+			"tags":  []any{"a", 2},
+			"db":    map[string]any{"port": 5432},
+			"none":  nil,
+		}, v)
+	})
+
+	t.Run("test use number", func(t *testing.T) {
+		var v any
+		require.NoError(t, attribs.Unmarshal("id=12345678901234567890, price=19.99, tags[1], obj(x=0x10)", &v, attribs.Options{UseNumber: true}))
+		assert.Equal(t, map[string]any{
+			"id":    attribs.Number("12345678901234567890"),
+			"price": attribs.Number("19.99"),
+			"tags":  []any{attribs.Number("1")},
+			"obj":   map[string]any{"x": attribs.Number("0x10")},
+		}, v)
+
+		var def MarshalDef
+		require.NoError(t, attribs.Unmarshal("extra=1.5", &def, attribs.Options{UseNumber: true}))
+		assert.Equal(t, attribs.Number("1.5"), def.Extra)
+
+		d := attribs.Must(attribs.New(MarshalDef{}, attribs.Options{UseNumber: true}))
+		def, err := d.Parse("extra[1, 'x']", false)
+		require.NoError(t, err)
+		assert.Equal(t, []any{attribs.Number("1"), "x"}, def.Extra)
+	})
+
+	t.Run("test options", func(t *testing.T) {
+		var def Interval
+		require.NoError(t, attribs.Unmarshal("start=1 # comment", &def, attribs.Options{Parser: parser.Options{Comments: true}}))
+		assert.Equal(t, 1, def.Start)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		var def MarshalDef
+		err := attribs.Unmarshal("big='x'", &def)
+		var pe parser.ParseError
+		require.ErrorAs(t, err, &pe)
+		assert.Equal(t, 0, pe.Position())
+
+		assert.ErrorIs(t, attribs.Unmarshal("id=1", def), attribs.ErrInvalidTarget)
+		assert.ErrorIs(t, attribs.Unmarshal("id=1", (*MarshalDef)(nil)), attribs.ErrInvalidTarget)
+		assert.ErrorIs(t, attribs.Unmarshal("id=1", new(int)), attribs.ErrInvalidTarget)
+		assert.ErrorIs(t, attribs.Unmarshal("id=1", new(map[int]int)), attribs.ErrMapKeyNotStr)
+		assert.Error(t, attribs.Unmarshal("unknown=1", &def))
+	})
+}
+
+func TestMarshal(t *testing.T) {
+	t.Run("test struct", func(t *testing.T) {
+		def := MarshalDef{
+			MarshalMixin: MarshalMixin{Label: "x"},
+			Name:         "user",
+			ID:           -1,
+			Ratio:        2,
+			Enabled:      true,
+			Optional:     ptr(uint8(7)),
+			Span:         &Interval{End: 2},
+			Tags:         []string{"a"},
+			Headers:      map[string]string{"Content-Type": "json", "accept": "*"},
+			Extra:        map[string]any{"n": []any{1, nil}},
+			Big:          "0x10",
+			Hidden:       "hidden",
+		}
+		out, err := attribs.Marshal(&def)
+		require.NoError(t, err)
+		assert.Equal(t, "'user', '', label=x, id=-1, ratio=2.0, enabled=true, optional=7, span(start=0, end=2), tags['a'], "+
+			"headers('Content-Type'=json, accept='*'), extra(n[1, null]), big=0x10", out)
+
+		var decoded MarshalDef
+		require.NoError(t, attribs.Unmarshal(out, &decoded))
+		def.Hidden = ""
+		def.Extra = map[string]any{"n": []any{1, nil}}
+		assert.Equal(t, def, decoded)
+	})
+
+	t.Run("test values", func(t *testing.T) {
+		for _, item := range []struct {
+			value    any
+			expected string
+		}{
+			{value: Interval{}, expected: "start=0, end=0"},
+			{value: (*Interval)(nil), expected: ""},
+			{value: map[string]any{"b": 1.5, "a": "x y", "": true, "db.port": 1}, expected: "''=true, a='x y', b=1.5, 'db.port'=1"},
+			{value: map[string]float64{"inf": math.Inf(-1), "e": 1e21}, expected: "e=1e+21, inf=-Inf"},
+			{value: map[string]float64{"inf": math.Inf(1)}, expected: "inf=+Inf"},
+			{value: map[string]attribs.Number{"zero": ""}, expected: "zero=0"},
+			{value: map[string][2]bool{"pair": {true, false}}, expected: "pair[true, false]"},
+		} {
+			out, err := attribs.Marshal(item.value)
+			require.NoError(t, err)
+			assert.Equal(t, item.expected, out)
+		}
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		_, err := attribs.Marshal(1)
+		assert.ErrorIs(t, err, attribs.ErrUnsupportedType)
+		_, err = attribs.Marshal(map[string]any{"f": func() {}})
+		assert.ErrorIs(t, err, attribs.ErrUnsupportedType)
+		_, err = attribs.Marshal(map[int]int{1: 1})
+		assert.ErrorIs(t, err, attribs.ErrMapKeyNotStr)
+	})
+}
+
+func TestNumber(t *testing.T) {
+	n := attribs.Number("0x1_0")
+	i, err := n.Int64()
+	require.NoError(t, err)
+	assert.Equal(t, int64(16), i)
+	f, err := n.Float64()
+	require.NoError(t, err)
+	assert.Equal(t, 16.0, f)
+	assert.Equal(t, "0x1_0", n.String())
+
+	_, err = attribs.Number("1.5").Int64()
+	assert.Error(t, err)
+
+	bi, err := attribs.Number("12345678901234567890").BigInt()
+	require.NoError(t, err)
+	assert.Equal(t, "12345678901234567890", bi.String())
+	bf, err := attribs.Number("19.99").BigFloat()
+	require.NoError(t, err)
+	assert.Equal(t, "19.99", bf.Text('f', 2))
+	br, err := attribs.Number("19.99").BigRat()
+	require.NoError(t, err)
+	assert.Equal(t, "1999/100", br.RatString())
+	_, err = attribs.Number("NaN").BigFloat()
+	assert.Error(t, err)
+}
+
+func TestBigNumbers(t *testing.T) {
+	type Price struct {
+		ID     *big.Int   `attr:"name=id"`
+		Amount big.Rat    `attr:"name=amount"`
+		Ratio  *big.Float `attr:"name=ratio"`
+		Parts  []*big.Int `attr:"name=parts"`
+	}
+
+	d := attribs.Must(attribs.New(Price{}))
+
+	t.Run("test parse", func(t *testing.T) {
+		price, err := d.Parse("id=123_456_789_012_345_678_901, amount=19.99, ratio=0x1.8p1, parts[1, -0xFF]", false)
+		require.NoError(t, err)
+		assert.Equal(t, "123456789012345678901", price.ID.String())
+		assert.Equal(t, "1999/100", price.Amount.RatString())
+		assert.Equal(t, "3", price.Ratio.String())
+		require.Len(t, price.Parts, 2)
+		assert.Equal(t, "-255", price.Parts[1].String())
+	})
+
+	t.Run("test invalid", func(t *testing.T) {
+		for _, input := range []string{"id=1.5", "amount=Inf", "ratio=NaN", "id='1'"} {
+			_, err := d.Parse(input, false)
+			var pe parser.ParseError
+			assert.ErrorAs(t, err, &pe, "input: %q", input)
+		}
+	})
+
+	t.Run("test marshal", func(t *testing.T) {
+		price := Price{ID: big.NewInt(7), Ratio: big.NewFloat(0.5), Parts: []*big.Int{big.NewInt(1)}}
+		price.Amount.SetString("19.99")
+		out, err := attribs.Marshal(price)
+		require.NoError(t, err)
+		assert.Equal(t, "id=7, amount=19.99, ratio=0.5, parts[1]", out)
+
+		decoded, err := d.Parse(out, false)
+		require.NoError(t, err)
+		assert.Equal(t, 0, price.Amount.Cmp(&decoded.Amount))
+
+		price.Amount.SetFrac64(1, 3)
+		_, err = attribs.Marshal(price)
+		assert.ErrorIs(t, err, attribs.ErrUnsupportedType)
+	})
+}
@@ -0,0 +1,87 @@
+package attribs
+
+import (
+	"math/big"
+	"reflect"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+var (
+	numberType   = reflect.TypeOf(Number(""))
+	bigIntType   = reflect.TypeOf(big.Int{})
+	bigFloatType = reflect.TypeOf(big.Float{})
+	bigRatType   = reflect.TypeOf(big.Rat{})
+)
+
+// Number is number literal as written in input (0x10, 1_000.5), it works like json.Number. Fields of type Number
+// accept any number without loss of precision, and any values hold Number when Options.UseNumber is set.
+type Number string
+
+// String returns number literal
+func (n Number) String() string {
+	return string(n)
+}
+
+// Int64 returns number as int64, it accepts all integer literals (0x10, 0b1010, 1_000)
+func (n Number) Int64() (int64, error) {
+	return parser.ParseInt(string(n), 64)
+}
+
+// Float64 returns number as float64
+func (n Number) Float64() (float64, error) {
+	return parser.ParseFloat(string(n), 64)
+}
+
+// BigInt returns number as big.Int, so integers of any size are supported
+func (n Number) BigInt() (*big.Int, error) {
+	return parser.ParseBigInt(string(n))
+}
+
+// BigFloat returns number as big.Float with precision high enough for all digits of number
+func (n Number) BigFloat() (*big.Float, error) {
+	return parser.ParseBigFloat(string(n), 0)
+}
+
+// BigRat returns number as exact rational number (19.99 is 1999/100)
+func (n Number) BigRat() (*big.Rat, error) {
+	return parser.ParseBigRat(string(n))
+}
+
+// buildAny builds value for any target, it's parser.Attribute.Build with numbers as Number when useNumber is set
+func buildAny(parsed *parser.Attribute, options setOptions) (reflect.Value, error) {
+	if !options.useNumber {
+		return parsed.Build()
+	}
+
+	switch {
+	case parsed.Value != nil && parsed.Value.Number != nil && !parsed.Value.Null:
+		return reflect.ValueOf(Number(*parsed.Value.Number)), nil
+	case parsed.Object != nil:
+		result := reflect.ValueOf(make(map[string]any, len(parsed.Object.Attributes)))
+		attrs, err := parsed.Object.ExpandPaths()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		for _, attr := range attrs.Attributes {
+			value, err := buildAny(attr, options)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result.SetMapIndex(reflect.ValueOf(attr.Name), value)
+		}
+		return result, nil
+	case parsed.Array != nil:
+		result := make([]any, 0, len(parsed.Array.Attributes))
+		for _, attr := range parsed.Array.Attributes {
+			value, err := buildAny(attr, options)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			result = append(result, value.Interface())
+		}
+		return reflect.ValueOf(result), nil
+	}
+
+	return parsed.Build()
+}
@@ -0,0 +1,18 @@
+package attribs
+
+import "github.com/phonkee/attribs/parser"
+
+// Options for definition, zero value is strict mode suitable for struct tags
+type Options struct {
+	// Parser options used when parsing input (e.g. comments)
+	Parser parser.Options
+
+	// UseNumber sets numbers to any fields, []any and map[string]any values as Number (number literal as written),
+	// so no precision is lost. Without it numbers are int or float64 (same as parser.Value.BuildValue).
+	UseNumber bool
+
+	// NameSource is struct tag key (json, yaml, mapstructure) used for names of fields without attr tag, so existing
+	// DTOs can be used as definitions. Options after comma (omitempty) are ignored, "-" and names that are not valid
+	// attribute names (content-type) skip the field.
+	NameSource string
+}
@@ -2,7 +2,7 @@ package parser
 
 import (
 	"reflect"
-	"strconv"
+	"strings"
 )
 
 // Attribute representation
@@ -10,6 +10,16 @@ type Attribute struct {
 	// name of attribute
 	Name string
 
+	// Quoted is true when name was written as quoted string ('Content-Type'=json)
+	Quoted bool
+
+	// Path holds segments of dotted name (db.pool.max=10 has Name "db.pool.max"), it's nil for simple names.
+	// Use Attributes.ExpandPaths to resolve paths into nested objects.
+	Path []string
+
+	// Flag is true for bare boolean flag (required), Value is then true and shares span with attribute
+	Flag bool
+
 	// span in original string
 	Span *SourceSpan
 
@@ -19,6 +29,9 @@ type Attribute struct {
 	// Object and array values
 	Object *Attributes
 	Array  *Attributes
+
+	// Comments found in input, set only on top-level attribute when Options.Comments is enabled
+	Comments []*Comment
 }
 
 type Attributes struct {
@@ -53,7 +66,11 @@ func (a *Attribute) Build() (reflect.Value, error) {
 		mt := reflect.MapOf(reflect.TypeOf(""), reflect.TypeOf((*any)(nil)).Elem())
 		newValue := reflect.MakeMapWithSize(mt, 0)
 
-		for _, attr := range a.Object.Attributes {
+		attrs, err := a.Object.ExpandPaths()
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		for _, attr := range attrs.Attributes {
 			value, err := attr.Build()
 			if err != nil {
 				return reflect.Value{}, err
@@ -84,9 +101,9 @@ func (a *Attribute) PrepareAny() (reflect.Value, error) {
 		case a.Value.String != nil:
 			return reflect.ValueOf(""), nil
 		case a.Value.Number != nil:
-			_, err := strconv.ParseInt(*a.Value.Number, 10, 64)
+			_, err := ParseInt(*a.Value.Number, 64)
 			if err != nil {
-				_, err := strconv.ParseFloat(*a.Value.Number, 64)
+				_, err := ParseFloat(*a.Value.Number, 64)
 				if err != nil {
 					return reflect.Value{}, NewParseError(a.Span, "Invalid number: %s", *a.Value.Number)
 				}
@@ -104,3 +121,66 @@ func (a *Attribute) PrepareAny() (reflect.Value, error) {
 	}
 	return reflect.Value{}, NewParseError(a.Span, "Attribute has no value")
 }
+
+// ExpandPaths returns attributes where dotted paths are merged into objects by their first segment,
+// so db.pool.max=10, db.pool.min=1 becomes db(pool.max=10, pool.min=1). Remaining segments are resolved by calling
+// ExpandPaths on the nested object. Path into attribute that is defined explicitly (db(...) or db=1) is reported
+// as ConflictError. Attributes without paths are returned as they are.
+func (a *Attributes) ExpandPaths() (*Attributes, error) {
+	hasPath := false
+	for _, attr := range a.Attributes {
+		if attr.Path != nil {
+			hasPath = true
+			break
+		}
+	}
+	if !hasPath {
+		return a, nil
+	}
+
+	result := newAttributes(a.Span)
+	// objects created from paths and first path that created them (for error reporting)
+	objects := make(map[string]*Attribute)
+	paths := make(map[string]*Attribute)
+	explicit := make(map[string]*Attribute)
+
+	for _, attr := range a.Attributes {
+		if attr.Path == nil {
+			// positional attributes have no name, so they cannot conflict
+			if attr.Name != "" || attr.Quoted {
+				if path, ok := paths[attr.Name]; ok {
+					return nil, NewConflictError(attr.Span, path.Span, "attribute %s conflicts with path %s", attr.Name, path.Name)
+				}
+				explicit[attr.Name] = attr
+			}
+			result.Push(attr)
+			continue
+		}
+
+		head := attr.Path[0]
+		if other, ok := explicit[head]; ok {
+			return nil, NewConflictError(attr.Span, other.Span, "path %s conflicts with attribute %s", attr.Name, other.Name)
+		}
+		object, ok := objects[head]
+		if !ok {
+			object = &Attribute{Name: head, Span: attr.Span, Object: newAttributes(attr.Span)}
+			objects[head] = object
+			paths[head] = attr
+			result.Push(object)
+		}
+		object.Object.Push(attr.trimPath())
+	}
+
+	return result, nil
+}
+
+// trimPath returns copy of attribute without first path segment.
+func (a *Attribute) trimPath() *Attribute {
+	result := *a
+	result.Name = strings.Join(a.Path[1:], ".")
+	result.Path = a.Path[1:]
+	if len(result.Path) < 2 {
+		result.Path = nil
+	}
+	return &result
+}
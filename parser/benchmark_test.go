@@ -0,0 +1,121 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ─── inputs ─────────────────────────────────────────────────────────────────
+
+const (
+	benchSmallTag = "name='user_id', required, span(start=0, end=255), tags['id', 'primary']"
+)
+
+// benchNested returns an object nested depth levels deep: a(a(a(... value=1 ...)))
+func benchNested(depth int) string {
+	return strings.Repeat("a(", depth) + "value=1" + strings.Repeat(")", depth)
+}
+
+// benchArray returns an array with size integer items.
+func benchArray(size int) string {
+	items := make([]string, size)
+	for i := range items {
+		items[i] = strconv.Itoa(i)
+	}
+	return "ids[" + strings.Join(items, ", ") + "]"
+}
+
+// benchLongString returns a quoted string of roughly size runes, with an escape every 16 runes.
+func benchLongString(size int) string {
+	var sb strings.Builder
+	sb.WriteString(`text="`)
+	for i := 0; i < size; i++ {
+		if i%16 == 15 {
+			sb.WriteString(`\n`)
+			continue
+		}
+		sb.WriteByte('a' + byte(i%26))
+	}
+	sb.WriteString(`"`)
+	return sb.String()
+}
+
+// ─── benchmarks ─────────────────────────────────────────────────────────────
+
+func benchmarkParse(b *testing.B, input string) {
+	b.Helper()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for b.Loop() {
+		if _, err := Parse(strings.NewReader(input)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkLex(b *testing.B, input string) {
+	b.Helper()
+	b.ReportAllocs()
+	b.SetBytes(int64(len(input)))
+	for b.Loop() {
+		l := newLexer(strings.NewReader(input))
+		for {
+			_, tok, val := l.Lex()
+			if tok == TokenError {
+				b.Fatal(val)
+			}
+			if tok == TokenEOF {
+				break
+			}
+		}
+	}
+}
+
+func BenchmarkLex(b *testing.B) {
+	b.Run("small_tag", func(b *testing.B) { benchmarkLex(b, benchSmallTag) })
+	b.Run("nested_32", func(b *testing.B) { benchmarkLex(b, benchNested(32)) })
+	b.Run("array_1000", func(b *testing.B) { benchmarkLex(b, benchArray(1000)) })
+	b.Run("long_string_4096", func(b *testing.B) { benchmarkLex(b, benchLongString(4096)) })
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.Run("empty", func(b *testing.B) { benchmarkParse(b, "") })
+	b.Run("small_tag", func(b *testing.B) { benchmarkParse(b, benchSmallTag) })
+	b.Run("nested_8", func(b *testing.B) { benchmarkParse(b, benchNested(8)) })
+	b.Run("nested_32", func(b *testing.B) { benchmarkParse(b, benchNested(32)) })
+	b.Run("array_100", func(b *testing.B) { benchmarkParse(b, benchArray(100)) })
+	b.Run("array_1000", func(b *testing.B) { benchmarkParse(b, benchArray(1000)) })
+	b.Run("long_string_256", func(b *testing.B) { benchmarkParse(b, benchLongString(256)) })
+	b.Run("long_string_4096", func(b *testing.B) { benchmarkParse(b, benchLongString(4096)) })
+}
+
+// ─── allocation budgets ─────────────────────────────────────────────────────
+
+// TestParseAllocs locks in allocation ceilings for common inputs, so regressions in lexer/parser show up in tests.
+// Ceilings are about 8% above the measured values (empty 17, single_kv 61, small_tag 520, nested_8 400,
+// array_100 3073); lower them when an optimization lands.
+func TestParseAllocs(t *testing.T) {
+	for _, item := range []struct {
+		name   string
+		input  string
+		budget float64
+	}{
+		{name: "empty", input: "", budget: 19},
+		{name: "single_kv", input: "id=42", budget: 66},
+		{name: "small_tag", input: benchSmallTag, budget: 560},
+		{name: "nested_8", input: benchNested(8), budget: 425},
+		{name: "array_100", input: benchArray(100), budget: 3300},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			allocs := testing.AllocsPerRun(20, func() {
+				if _, err := Parse(strings.NewReader(item.input)); err != nil {
+					t.Fatal(err)
+				}
+			})
+			if allocs > item.budget {
+				t.Errorf("Parse(%q) allocated %.0f times, budget is %.0f", item.input, allocs, item.budget)
+			}
+		})
+	}
+}
@@ -0,0 +1,587 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+var (
+	// ErrPositional is returned when name of positional value or array item is edited
+	ErrPositional = errors.New("positional value has no name")
+	// ErrDetached is returned when node that is not in tree (removed or root) is edited
+	ErrDetached = errors.New("node is not in tree")
+	// ErrUnsupportedSyntax is returned by ParseCST for other syntax than attribute grammar
+	ErrUnsupportedSyntax = errors.New("syntax is not supported")
+)
+
+// CST is lossless concrete syntax tree. It keeps every token of input including whitespace, comments, original
+// quotes and number literals, so String returns input byte for byte. Nodes can be edited and untouched parts of
+// text are kept as they were written.
+type CST struct {
+	options Options
+
+	// head is first token, tail is EOF token which holds trailing whitespace and comments
+	head *cstToken
+	tail *cstToken
+
+	root *Node
+}
+
+// cstToken is token of CST with whitespace and comments that precede it
+type cstToken struct {
+	kind    Token
+	leading string
+	text    string
+	prev    *cstToken
+	next    *cstToken
+}
+
+// Node of CST is attribute, positional value or array item together with its tokens. Attribute of node is kept
+// in sync with edits, spans of edited parts are relative to text passed to the edit.
+type Node struct {
+	cst      *CST
+	parent   *Node
+	attr     *Attribute
+	children []*Node
+
+	// first and last token of node (nil for root)
+	first *cstToken
+	last  *cstToken
+
+	// last token of name, nil for positional values and array items
+	name *cstToken
+
+	// brackets of object or array value, root has EOF as close
+	open  *cstToken
+	close *cstToken
+	array bool
+}
+
+// ParseCST parses input into concrete syntax tree. Input is validated by Parse with the same options, only
+// attribute grammar is supported (Options.Syntax must be nil).
+func ParseCST(input io.Reader, options ...Options) (*CST, error) {
+	content, err := io.ReadAll(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(options) > 0 && options[0].Syntax != nil {
+		return nil, ErrUnsupportedSyntax
+	}
+
+	parsed, err := Parse(strings.NewReader(string(content)), options...)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &CST{}
+	if len(options) > 0 {
+		result.options = options[0]
+	}
+	result.head, result.tail = tokenize(string(content), result.options)
+	result.root = result.build(parsed)
+	return result, nil
+}
+
+// Root returns root node, its children are top-level attributes
+func (c *CST) Root() *Node {
+	return c.root
+}
+
+// String returns text of tree, it's identical to input until tree is edited
+func (c *CST) String() string {
+	var sb strings.Builder
+	for token := c.head; token != nil; token = token.next {
+		sb.WriteString(token.leading)
+		sb.WriteString(token.text)
+	}
+	return sb.String()
+}
+
+// Attribute returns AST of node
+func (n *Node) Attribute() *Attribute {
+	return n.attr
+}
+
+// Name returns name of attribute, it's empty for positional values and array items
+func (n *Node) Name() string {
+	return n.attr.Name
+}
+
+// Parent returns parent node, it's nil for root and removed nodes
+func (n *Node) Parent() *Node {
+	return n.parent
+}
+
+// Children returns attributes of object or items of array
+func (n *Node) Children() []*Node {
+	return n.children
+}
+
+// Child returns first child with given name or nil
+func (n *Node) Child(name string) *Node {
+	for _, child := range n.children {
+		if child.attr.Name == name && (name != "" || child.attr.Quoted) {
+			return child
+		}
+	}
+	return nil
+}
+
+// Text returns source text of node without whitespace and comments before it
+func (n *Node) Text() string {
+	if n.first == nil {
+		return n.cst.String()
+	}
+	var sb strings.Builder
+	sb.WriteString(n.first.text)
+	for token := n.first; token != n.last; {
+		token = token.next
+		sb.WriteString(token.leading)
+		sb.WriteString(token.text)
+	}
+	return sb.String()
+}
+
+// SetName renames attribute. Name is written as identifier (or dotted path) when possible, otherwise it's quoted
+// with quotes of original name (single quotes by default).
+func (n *Node) SetName(name string) error {
+	if err := n.editable(); err != nil {
+		return err
+	}
+	if n.name == nil {
+		return ErrPositional
+	}
+
+	segments := strings.Split(name, ".")
+	raw := true
+	for _, segment := range segments {
+		raw = raw && isIdentifier(segment)
+	}
+
+	text := name
+	if !raw {
+		p := printer{}
+		if n.first.kind == TokenString && strings.HasPrefix(n.first.text, `"`) {
+			p.options.Quote = QuoteDouble
+		}
+		text = p.quote(name)
+	}
+
+	first, tail := tokenize(text, Options{})
+	last := tail.prev
+	last.next, tail.prev = nil, nil
+	first.leading = n.first.leading
+
+	n.cst.replace(n.first, n.name, first, last)
+	if n.last == n.name {
+		n.last = last
+	}
+	n.first, n.name = first, last
+
+	n.attr.Name = name
+	n.attr.Quoted = !raw
+	n.attr.Path = nil
+	if raw && len(segments) > 1 {
+		n.attr.Path = segments
+	}
+	return nil
+}
+
+// SetValue replaces value of node with value written in text (e.g. 'hello', 42, (a=1) or [1, 2]). Whitespace
+// before replaced value is kept.
+func (n *Node) SetValue(text string) error {
+	if err := n.editable(); err != nil {
+		return err
+	}
+
+	var input string
+	switch {
+	case n.name == nil:
+		input = text
+	case strings.HasPrefix(strings.TrimSpace(text), "("), strings.HasPrefix(strings.TrimSpace(text), "["):
+		input = "_" + text
+	default:
+		input = "_=" + text
+	}
+	repl, err := n.cst.snippet(input, n.parent.array)
+	if err != nil {
+		return err
+	}
+	if (repl.name == nil) != (n.name == nil) || repl.name != nil && repl.attr.Path != nil {
+		return fmt.Errorf("%w: %q", ErrNotValue, text)
+	}
+
+	oldStart, newStart := n.valueStart(), repl.valueStart()
+	if newStart == nil {
+		return fmt.Errorf("%w: %q", ErrNotValue, text)
+	}
+	// keep '=' (and whitespace around it) when both values have it
+	if oldStart != nil && oldStart.kind == TokenEqual && newStart.kind == TokenEqual {
+		oldStart, newStart = oldStart.next, newStart.next
+	}
+	newStart.prev = nil
+
+	if oldStart == nil {
+		newStart.leading = ""
+		n.cst.insertAfter(n.last, newStart, repl.last)
+	} else {
+		newStart.leading = oldStart.leading
+		n.cst.replace(oldStart, n.last, newStart, repl.last)
+		if n.first == oldStart {
+			n.first = newStart
+		}
+	}
+	n.last = repl.last
+
+	n.open, n.close, n.array = repl.open, repl.close, repl.array
+	n.children = repl.children
+	for _, child := range n.children {
+		child.parent = n
+	}
+	n.attr.Value, n.attr.Object, n.attr.Array, n.attr.Flag = repl.attr.Value, repl.attr.Object, repl.attr.Array, false
+	return nil
+}
+
+// Remove removes node together with comma that separates it from its siblings. Comments before node are
+// removed with it.
+func (n *Node) Remove() error {
+	if err := n.editable(); err != nil {
+		return err
+	}
+
+	parent := n.parent
+	index := parent.indexOf(n)
+	first, last := n.first, n.last
+	switch {
+	case last.next.kind == TokenComma:
+		last = last.next
+		// next attribute becomes first, so it takes whitespace of removed one
+		if index == 0 && len(parent.children) > 1 {
+			if next := parent.children[1].first; strings.TrimSpace(next.leading) == "" {
+				next.leading = n.first.leading
+			}
+		}
+	case index > 0:
+		first = parent.children[index-1].last.next
+	}
+	n.cst.unlink(first, last)
+
+	parent.children = append(parent.children[:index:index], parent.children[index+1:]...)
+	list := parent.list()
+	list.Attributes = append(list.Attributes[:index:index], list.Attributes[index+1:]...)
+	n.detach()
+	return nil
+}
+
+// InsertAfter inserts attribute (or array item) written in text after node and returns its node. Separator
+// follows whitespace of surrounding attributes (same line or new line with the same indentation).
+func (n *Node) InsertAfter(text string) (*Node, error) {
+	if err := n.editable(); err != nil {
+		return nil, err
+	}
+
+	parent := n.parent
+	node, err := n.cst.snippet(text, parent.array)
+	if err != nil {
+		return nil, err
+	}
+
+	index := parent.indexOf(n)
+	separator := " "
+	switch {
+	case index+1 < len(parent.children):
+		separator = parent.children[index+1].first.leading
+	case index > 0:
+		separator = n.first.leading
+	}
+	switch {
+	case strings.Contains(separator, "\n"):
+		separator = separator[strings.LastIndex(separator, "\n"):]
+	case separator != "":
+		separator = " "
+	}
+	node.first.leading = separator
+
+	// new attribute goes after existing comma, so whitespace around it stays with the node it was written for
+	comma := &cstToken{kind: TokenComma, text: ","}
+	if existing := n.last.next; existing.kind == TokenComma {
+		n.cst.insertAfter(existing, node.first, node.last)
+		n.cst.insertAfter(node.last, comma, comma)
+	} else {
+		n.cst.insertAfter(n.last, comma, comma)
+		n.cst.insertAfter(comma, node.first, node.last)
+	}
+
+	parent.insertChild(index+1, node)
+	return node, nil
+}
+
+// Append adds attribute (or array item) written in text at the end of object, array or root node and returns its
+// node.
+func (n *Node) Append(text string) (*Node, error) {
+	if n.cst == nil {
+		return nil, ErrDetached
+	}
+	if n.close == nil {
+		return nil, fmt.Errorf("%w: cannot append to %s", ErrNotObject, n.attr.Name)
+	}
+	if len(n.children) > 0 {
+		return n.children[len(n.children)-1].InsertAfter(text)
+	}
+
+	node, err := n.cst.snippet(text, n.array)
+	if err != nil {
+		return nil, err
+	}
+	node.first.leading = ""
+	n.cst.insertAfter(n.close.prev, node.first, node.last)
+	n.insertChild(0, node)
+	return node, nil
+}
+
+// editable returns error when node cannot be edited
+func (n *Node) editable() error {
+	if n.cst == nil || n.parent == nil {
+		return ErrDetached
+	}
+	return nil
+}
+
+// valueStart returns first token of value ('=' for key=value), nil for bare flag
+func (n *Node) valueStart() *cstToken {
+	if n.name == nil {
+		return n.first
+	}
+	if n.name == n.last {
+		return nil
+	}
+	return n.name.next
+}
+
+// list returns AST list of children
+func (n *Node) list() *Attributes {
+	if n.array {
+		return n.attr.Array
+	}
+	return n.attr.Object
+}
+
+func (n *Node) indexOf(child *Node) int {
+	for i, c := range n.children {
+		if c == child {
+			return i
+		}
+	}
+	return -1
+}
+
+// insertChild inserts child node (and its attribute) at index
+func (n *Node) insertChild(index int, child *Node) {
+	child.parent = n
+	child.setCST(n.cst)
+	n.children = append(n.children[:index:index], append([]*Node{child}, n.children[index:]...)...)
+	list := n.list()
+	list.Attributes = append(list.Attributes[:index:index], append([]*Attribute{child.attr}, list.Attributes[index:]...)...)
+}
+
+func (n *Node) setCST(c *CST) {
+	n.cst = c
+	for _, child := range n.children {
+		child.setCST(c)
+	}
+}
+
+// detach marks node and its children as removed from tree
+func (n *Node) detach() {
+	n.parent = nil
+	n.setCST(nil)
+}
+
+// snippet parses text of single attribute (array item when array is true) and returns its detached node
+func (c *CST) snippet(text string, array bool) (*Node, error) {
+	input := text
+	if array {
+		input = "_[" + text + "]"
+	}
+	parsed, err := Parse(strings.NewReader(input), c.options)
+	if err != nil {
+		return nil, err
+	}
+
+	tree := &CST{options: c.options}
+	tree.head, tree.tail = tokenize(input, c.options)
+	list := tree.build(parsed)
+	if array {
+		list = list.children[0]
+	}
+	if len(list.children) != 1 {
+		return nil, fmt.Errorf("%w: expected single attribute: %q", ErrNotValue, text)
+	}
+
+	node := list.children[0]
+	tree.unlink(node.first, node.last)
+	node.parent = nil
+	return node, nil
+}
+
+// build builds nodes for parsed attribute over tokens of tree, tokens were validated by parser
+func (c *CST) build(parsed *Attribute) *Node {
+	root := &Node{cst: c, attr: parsed, close: c.tail}
+	b := &cstBuilder{cst: c, token: c.head}
+	b.list(root)
+	return root
+}
+
+// cstBuilder builds nodes from tokens, it follows grammar of parser but doesn't check errors
+type cstBuilder struct {
+	cst   *CST
+	token *cstToken
+}
+
+func (b *cstBuilder) advance() *cstToken {
+	result := b.token
+	b.token = result.next
+	return result
+}
+
+// list builds children of parent until closing bracket or EOF
+func (b *cstBuilder) list(parent *Node) {
+	attrs := parent.list().Attributes
+	for {
+		switch b.token.kind {
+		case TokenEOF, TokenCloseBracket, TokenCloseSquareBracket:
+			return
+		case TokenComma:
+			b.advance()
+			continue
+		}
+
+		node := &Node{cst: b.cst, parent: parent, attr: attrs[len(parent.children)], first: b.token}
+		if parent.array {
+			b.item(node)
+		} else {
+			b.attribute(node)
+		}
+		parent.children = append(parent.children, node)
+	}
+}
+
+func (b *cstBuilder) attribute(node *Node) {
+	switch b.token.kind {
+	case TokenIdent:
+		node.name = b.advance()
+		for b.token.kind == TokenDot {
+			b.advance()
+			node.name = b.advance()
+		}
+		b.value(node)
+	case TokenString:
+		token := b.advance()
+		switch b.token.kind {
+		case TokenEqual, TokenOpenBracket, TokenOpenSquareBracket:
+			node.name = token
+			b.value(node)
+		default:
+			node.last = token
+		}
+	default:
+		b.item(node)
+	}
+}
+
+// value builds value of named attribute
+func (b *cstBuilder) value(node *Node) {
+	switch b.token.kind {
+	case TokenEqual:
+		b.advance()
+		node.last = b.advance()
+	case TokenOpenBracket, TokenOpenSquareBracket:
+		b.brackets(node)
+	default:
+		// bare flag
+		node.last = node.name
+	}
+}
+
+// item builds positional value or array item
+func (b *cstBuilder) item(node *Node) {
+	switch b.token.kind {
+	case TokenOpenBracket, TokenOpenSquareBracket:
+		b.brackets(node)
+	default:
+		node.last = b.advance()
+	}
+}
+
+func (b *cstBuilder) brackets(node *Node) {
+	node.open = b.advance()
+	node.array = node.open.kind == TokenOpenSquareBracket
+	b.list(node)
+	node.close = b.advance()
+	node.last = node.close
+}
+
+// tokenize lexes content into linked tokens and returns first token and EOF token, content must be valid
+func tokenize(content string, options Options) (head, tail *cstToken) {
+	l := newLexer(strings.NewReader(content), options)
+	loc := newLocator(content)
+
+	end := 0
+	for {
+		span, tok, _ := l.Lex()
+		start := loc.location(span.Position).Offset
+		if tok == TokenEOF || tok == TokenError {
+			start = len(content)
+		}
+		token := &cstToken{kind: tok, leading: content[end:start], text: content[start:l.offset], prev: tail}
+		if tail == nil {
+			head = token
+		} else {
+			tail.next = token
+		}
+		tail, end = token, l.offset
+		if tok == TokenEOF || tok == TokenError {
+			return head, tail
+		}
+	}
+}
+
+// insertAfter links tokens first..last after token at (at the beginning when at is nil)
+func (c *CST) insertAfter(at, first, last *cstToken) {
+	next := c.head
+	if at != nil {
+		next = at.next
+		at.next = first
+	} else {
+		c.head = first
+	}
+	first.prev = at
+	last.next = next
+	if next != nil {
+		next.prev = last
+	}
+}
+
+// unlink removes tokens first..last from list
+func (c *CST) unlink(first, last *cstToken) {
+	prev, next := first.prev, last.next
+	if prev != nil {
+		prev.next = next
+	} else {
+		c.head = next
+	}
+	if next != nil {
+		next.prev = prev
+	}
+	first.prev, last.next = nil, nil
+}
+
+// replace replaces tokens oldFirst..oldLast by newFirst..newLast
+func (c *CST) replace(oldFirst, oldLast, newFirst, newLast *cstToken) {
+	prev := oldFirst.prev
+	c.unlink(oldFirst, oldLast)
+	c.insertAfter(prev, newFirst, newLast)
+}
@@ -0,0 +1,318 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkCST fails when text of tree is not parsed to the same structure as AST of tree (edits keep AST in sync)
+func checkCST(t *testing.T, tree *CST, options Options) {
+	t.Helper()
+	reparsed, err := Parse(strings.NewReader(tree.String()), options)
+	require.NoError(t, err, "text: %q", tree.String())
+	assert.Equal(t, structure(reparsed), structure(tree.Root().Attribute()), "text: %q", tree.String())
+}
+
+func TestParseCST(t *testing.T) {
+	t.Run("test lossless", func(t *testing.T) {
+		options := Options{Comments: true, TrailingCommas: true}
+		for _, input := range append(fuzzSeeds, "  a = 1 ,\n\t# comment\n\tb ( c = 'x' , d [ 1 , \"2\" ] ) , /* end */ \n") {
+			if _, err := Parse(strings.NewReader(input), options); err != nil {
+				continue
+			}
+			tree, err := ParseCST(strings.NewReader(input), options)
+			require.NoError(t, err)
+			assert.Equal(t, input, tree.String())
+			checkCST(t, tree, options)
+		}
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		_, err := ParseCST(strings.NewReader("a=1,,"))
+		var pe ParseError
+		assert.ErrorAs(t, err, &pe)
+	})
+
+	t.Run("test nodes", func(t *testing.T) {
+		tree, err := ParseCST(strings.NewReader(" 'pos', name = \"x\" , db.port=0x10, flag, obj( a=1 ), arr[ 1, (b=2) ]"))
+		require.NoError(t, err)
+
+		root := tree.Root()
+		require.Len(t, root.Children(), 6)
+		assert.Nil(t, root.Parent())
+		assert.Equal(t, "'pos'", root.Children()[0].Text())
+		assert.Equal(t, `name = "x"`, root.Child("name").Text())
+		assert.Equal(t, "db.port=0x10", root.Child("db.port").Text())
+		assert.Equal(t, "flag", root.Child("flag").Text())
+		assert.Equal(t, "obj( a=1 )", root.Child("obj").Text())
+		assert.Equal(t, "a=1", root.Child("obj").Child("a").Text())
+		assert.Equal(t, root.Child("obj"), root.Child("obj").Child("a").Parent())
+		assert.Equal(t, "(b=2)", root.Child("arr").Children()[1].Text())
+		assert.Equal(t, "0x10", *root.Child("db.port").Attribute().Value.Number)
+		assert.Nil(t, root.Child("missing"))
+	})
+}
+
+func TestCSTEdit(t *testing.T) {
+	for _, item := range []struct {
+		name     string
+		input    string
+		edit     func(root *Node) error
+		expected string
+	}{
+		{
+			name:     "set value keeps quotes and spacing of others",
+			input:    `a = "x" , b=0x10, c = 'y'`,
+			edit:     func(root *Node) error { return root.Child("b").SetValue("42") },
+			expected: `a = "x" , b=42, c = 'y'`,
+		},
+		{
+			name:     "set value keeps whitespace around equal",
+			input:    "a  =  1 // one",
+			edit:     func(root *Node) error { return root.Child("a").SetValue("'two'") },
+			expected: "a  =  'two' // one",
+		},
+		{
+			name:     "set value of flag",
+			input:    "required, b=1",
+			edit:     func(root *Node) error { return root.Child("required").SetValue("false") },
+			expected: "required=false, b=1",
+		},
+		{
+			name:     "set value to object",
+			input:    "a = 1, b",
+			edit:     func(root *Node) error { return root.Child("a").SetValue("(x=1, y[2])") },
+			expected: "a (x=1, y[2]), b",
+		},
+		{
+			name:  "set value of nested object",
+			input: "a(b=1, c(d='x'))",
+			edit: func(root *Node) error {
+				return root.Child("a").Child("c").Child("d").SetValue(`"y"`)
+			},
+			expected: `a(b=1, c(d="y"))`,
+		},
+		{
+			name:     "set value of positional",
+			input:    "'x', a=1",
+			edit:     func(root *Node) error { return root.Children()[0].SetValue("[1, 2]") },
+			expected: "[1, 2], a=1",
+		},
+		{
+			name:     "set value of array item",
+			input:    "arr[1, 2, 3]",
+			edit:     func(root *Node) error { return root.Child("arr").Children()[1].SetValue("x") },
+			expected: "arr[1, x, 3]",
+		},
+		{
+			name:  "set value of edited object",
+			input: "a=1",
+			edit: func(root *Node) error {
+				return chain(root.Child("a").SetValue("(b=1)"), root.Child("a").Child("b").SetValue("2"))
+			},
+			expected: "a(b=2)",
+		},
+		{
+			name:     "set name",
+			input:    "old_name = 1, other",
+			edit:     func(root *Node) error { return root.Child("old_name").SetName("new_name") },
+			expected: "new_name = 1, other",
+		},
+		{
+			name:     "set name of flag",
+			input:    "a, old",
+			edit:     func(root *Node) error { return root.Child("old").SetName("new") },
+			expected: "a, new",
+		},
+		{
+			name:     "set name to path",
+			input:    "a(b=1)",
+			edit:     func(root *Node) error { return root.Child("a").SetName("x.y") },
+			expected: "x.y(b=1)",
+		},
+		{
+			name:     "set name quoted",
+			input:    `"Content-Type"=json, a=1`,
+			edit:     func(root *Node) error { return root.Child("a").SetName("X-Y") },
+			expected: `"Content-Type"=json, 'X-Y'=1`,
+		},
+		{
+			name:     "set name keeps quotes",
+			input:    `"Content-Type"=json`,
+			edit:     func(root *Node) error { return root.Child("Content-Type").SetName("it's") },
+			expected: `"it's"=json`,
+		},
+		{
+			name:     "remove middle",
+			input:    "a=1, b=2, c=3",
+			edit:     func(root *Node) error { return root.Child("b").Remove() },
+			expected: "a=1, c=3",
+		},
+		{
+			name:     "remove first",
+			input:    "  a=1, b=2",
+			edit:     func(root *Node) error { return root.Child("a").Remove() },
+			expected: "  b=2",
+		},
+		{
+			name:     "remove last",
+			input:    "a=1, b=2 ",
+			edit:     func(root *Node) error { return root.Child("b").Remove() },
+			expected: "a=1 ",
+		},
+		{
+			name:     "remove only",
+			input:    "a( b=2 )",
+			edit:     func(root *Node) error { return root.Child("a").Child("b").Remove() },
+			expected: "a( )",
+		},
+		{
+			name:     "remove with comment",
+			input:    "a=1,\n# about b\nb=2,\nc=3\n",
+			edit:     func(root *Node) error { return root.Child("b").Remove() },
+			expected: "a=1,\nc=3\n",
+		},
+		{
+			name:     "remove keeps trailing comma",
+			input:    "a=1,\nb=2,\n",
+			edit:     func(root *Node) error { return root.Child("b").Remove() },
+			expected: "a=1,\n",
+		},
+		{
+			name:     "remove array item",
+			input:    "arr[1, 2, 3]",
+			edit:     func(root *Node) error { return root.Child("arr").Children()[2].Remove() },
+			expected: "arr[1, 2]",
+		},
+		{
+			name:  "insert after",
+			input: "a=1, c=3",
+			edit: func(root *Node) error {
+				_, err := root.Child("a").InsertAfter("b='2'")
+				return err
+			},
+			expected: "a=1, b='2', c=3",
+		},
+		{
+			name:  "insert after comma with whitespace",
+			input: "a = 1 , c = 3",
+			edit: func(root *Node) error {
+				_, err := root.Child("a").InsertAfter("b=2")
+				return err
+			},
+			expected: "a = 1 , b=2, c = 3",
+		},
+		{
+			name:  "insert after compact",
+			input: "a=1,c=3",
+			edit: func(root *Node) error {
+				_, err := root.Child("c").InsertAfter("d")
+				return err
+			},
+			expected: "a=1,c=3,d",
+		},
+		{
+			name:  "insert after multi-line",
+			input: "x(\n\ta=1,\n\tb=2,\n)",
+			edit: func(root *Node) error {
+				_, err := root.Child("x").Child("b").InsertAfter("c=3")
+				return err
+			},
+			expected: "x(\n\ta=1,\n\tb=2,\n\tc=3,\n)",
+		},
+		{
+			name:  "insert after array item",
+			input: "arr[1, 3]",
+			edit: func(root *Node) error {
+				_, err := root.Child("arr").Children()[0].InsertAfter("x")
+				return err
+			},
+			expected: "arr[1, x, 3]",
+		},
+		{
+			name:  "append",
+			input: "a=1, o(), arr[]",
+			edit: func(root *Node) error {
+				_, err := root.Append("z")
+				_, err2 := root.Child("o").Append("b=2")
+				_, err3 := root.Child("arr").Append("'x'")
+				return chain(err, err2, err3)
+			},
+			expected: "a=1, o(b=2), arr['x'], z",
+		},
+		{
+			name:  "append to empty root",
+			input: "",
+			edit: func(root *Node) error {
+				_, err := root.Append("a=1")
+				return err
+			},
+			expected: "a=1",
+		},
+		{
+			name:  "edit inserted node",
+			input: "a=1",
+			edit: func(root *Node) error {
+				node, err := root.Child("a").InsertAfter("b(c=1)")
+				if err != nil {
+					return err
+				}
+				_, err = node.Append("d=2")
+				return chain(err, node.Child("c").Remove())
+			},
+			expected: "a=1, b(d=2)",
+		},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			options := Options{Comments: true, TrailingCommas: true}
+			tree, err := ParseCST(strings.NewReader(item.input), options)
+			require.NoError(t, err)
+			require.NoError(t, item.edit(tree.Root()))
+			assert.Equal(t, item.expected, tree.String())
+			checkCST(t, tree, options)
+		})
+	}
+}
+
+func TestCSTEditError(t *testing.T) {
+	tree, err := ParseCST(strings.NewReader("'pos', a=1, arr[1]"))
+	require.NoError(t, err)
+	root := tree.Root()
+
+	assert.ErrorIs(t, root.Children()[0].SetName("x"), ErrPositional)
+	assert.ErrorIs(t, root.Child("arr").Children()[0].SetName("x"), ErrPositional)
+	assert.ErrorIs(t, root.Remove(), ErrDetached)
+	assert.ErrorIs(t, root.SetValue("1"), ErrDetached)
+	assert.ErrorIs(t, root.Child("a").SetValue("1, b=2"), ErrNotValue)
+	assert.ErrorIs(t, root.Children()[0].SetValue("b=2"), ErrNotValue)
+	_, err = root.Child("a").Append("b=1")
+	assert.ErrorIs(t, err, ErrNotObject)
+
+	var pe ParseError
+	assert.ErrorAs(t, root.Child("a").SetValue("'unterminated"), &pe)
+	_, err = root.Child("a").InsertAfter("b=")
+	assert.ErrorAs(t, err, &pe)
+
+	a := root.Child("a")
+	require.NoError(t, a.Remove())
+	assert.ErrorIs(t, a.Remove(), ErrDetached)
+	assert.ErrorIs(t, a.SetValue("2"), ErrDetached)
+	_, err = a.InsertAfter("b")
+	assert.ErrorIs(t, err, ErrDetached)
+
+	// failed edits don't change text
+	assert.Equal(t, "'pos', arr[1]", tree.String())
+}
+
+// chain returns first error
+func chain(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
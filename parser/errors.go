@@ -22,13 +22,24 @@ func ErrIsNoMatch(err error) bool {
 	return errors.Is(err, ErrNoMatch)
 }
 
-// ParseError defines error with additional information
-// Currently only position is supported, but in future it can be extended
+// ParseError defines error with location in parsed text
 type ParseError interface {
 	error
 
-	// Position in parsed text
+	// Position in parsed text (rune offset, 0-based)
 	Position() int
+
+	// Line in parsed text (1-based, 0 when location is unknown)
+	Line() int
+
+	// Column in parsed text (rune offset from start of line, 1-based, 0 when location is unknown)
+	Column() int
+
+	// Span of parsed text where error occurred
+	Span() *SourceSpan
+
+	// Message of error without location
+	Message() string
 }
 
 // NewParseError instantiates new parse error
@@ -51,3 +62,102 @@ func (p parseError) Error() string {
 func (p parseError) Position() int {
 	return p.span.Position
 }
+
+func (p parseError) Line() int {
+	return p.span.Start.Line
+}
+
+func (p parseError) Column() int {
+	return p.span.Start.Column
+}
+
+func (p parseError) Span() *SourceSpan {
+	return p.span
+}
+
+func (p parseError) Message() string {
+	return p.message
+}
+
+// ConflictError is ParseError that points to two places in parsed text, e.g. attribute defined twice
+type ConflictError interface {
+	ParseError
+
+	// ConflictPosition is position of the other (earlier) definition in parsed text
+	ConflictPosition() int
+
+	// ConflictSpan is span of the other (earlier) definition in parsed text
+	ConflictSpan() *SourceSpan
+}
+
+// NewConflictError instantiates new conflict error, span is where conflict was found and other is
+// where conflicting attribute was defined.
+func NewConflictError(span, other *SourceSpan, message string, args ...interface{}) ConflictError {
+	return conflictError{
+		parseError: parseError{
+			span:    span,
+			message: fmt.Sprintf(message, args...),
+		},
+		other: other,
+	}
+}
+
+type conflictError struct {
+	parseError
+	other *SourceSpan
+}
+
+func (c conflictError) Error() string {
+	return fmt.Sprintf("[span: %v] %v (conflicts with [span: %v])", c.span, c.message, c.other)
+}
+
+func (c conflictError) ConflictPosition() int {
+	return c.other.Position
+}
+
+func (c conflictError) ConflictSpan() *SourceSpan {
+	return c.other
+}
+
+// UnknownAttributeError is ParseError for attribute that is not defined, it carries the closest defined name, so
+// tools can offer quick fix.
+type UnknownAttributeError interface {
+	ParseError
+
+	// Name of unknown attribute
+	Name() string
+
+	// Suggestion is defined name closest to Name (see Suggest), empty when no name is close enough
+	Suggestion() string
+}
+
+// NewUnknownAttributeError instantiates new unknown attribute error for name at span, suggestion is added to message
+// as "did you mean" when it's not empty.
+func NewUnknownAttributeError(span *SourceSpan, name, suggestion string) UnknownAttributeError {
+	message := fmt.Sprintf("unknown attribute %s", name)
+	if suggestion != "" {
+		message += fmt.Sprintf(", did you mean %s?", suggestion)
+	}
+	return unknownAttributeError{
+		parseError: parseError{
+			span:    span,
+			message: message,
+		},
+		name:       name,
+		suggestion: suggestion,
+	}
+}
+
+type unknownAttributeError struct {
+	parseError
+	name       string
+	suggestion string
+}
+
+func (u unknownAttributeError) Name() string {
+	return u.name
+}
+
+func (u unknownAttributeError) Suggestion() string {
+	return u.suggestion
+}
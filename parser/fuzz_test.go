@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+// fuzzSeeds are taken from the lexer and parser table tests.
+var fuzzSeeds = []string{
+	"",
+	"=",
+	"(",
+	")",
+	"[",
+	"]",
+	",",
+	".",
+	"-",
+	"-x",
+	"'hello world'",
+	"'hello world",
+	`'hello \' world'`,
+	`'hello \\ world'`,
+	`'unknown \n escape'`,
+	`"hello\nworld"`,
+	`"unknown \t escape"`,
+	`"unterminated`,
+	`'\`,
+	`"\`,
+	`"\a\b\f\n\r\t\v\\\'\""`,
+	`"\x41\u00e9\U0001F600\0\101"`,
+	`"\q"`,
+	`"\x4"`,
+	"`raw \\d`",
+	"`unterminated",
+	"1234",
+	"1234xxx",
+	"1234.566",
+	"1234.566.888",
+	".888",
+	".888.123",
+	"-1234",
+	"-.1234",
+	"-1234.566.888",
+	"ident_12a",
+	"+5",
+	"1e6",
+	"0x1.8p1",
+	"0o755",
+	"0b1010",
+	"1_000_000",
+	"-Inf",
+	"0x",
+	"1__0",
+	"a=NaN",
+	"a=null, b[nil]",
+	"h('Content-Type'='json', 'x'(a=1), 'y'[1])",
+	"db.pool.max=10, db.pool.min=1",
+	"db.=1",
+	"sql=\"\"\"\n  SELECT *\n  FROM t\n\"\"\"",
+	"''''''",
+	"'''unterminated''",
+	"id=42",
+	"n=-7",
+	"f=-1.5",
+	"s='hello world'",
+	`s="hello world"`,
+	"flag",
+	"a=1, b='x', c",
+	"span(start=0, end=255)",
+	"outer()",
+	"a(b(c(d=1)))",
+	"ids[1, 2, 3]",
+	"ids[]",
+	"users[(username='alice', admin), (username='bob')]",
+	"rows[[1, 2, 3], [4, 5, 6]]",
+	"42",
+	"'positional', name=x",
+	"(a=1)",
+	"[1, 2]",
+	"id=",
+	"ids[1, 2",
+	"a,,b",
+	",",
+	"ids[[1, 2]",
+	"span(start=1",
+	")",
+	"]",
+	"a=1,",
+	"=1",
+	"name='user_id', required, span(start=0, end=255), tags['id', 'primary']",
+	"ž=1, 'ščť'",
+	"# comment\nid=1",
+	"id=1 // comment",
+	"a/* block */=1",
+	"/* unterminated",
+	"/**/",
+	"/",
+	"a=1,",
+	"a[1,], b(c=1,),",
+	"k='true', e='', d='a-b', c='\\x01\\xff'",
+}
+
+// checkSpanBounds fails when err is a ParseError whose span is not inside input.
+func checkSpanBounds(t *testing.T, input string, err error) {
+	t.Helper()
+	var pe ParseError
+	if !errors.As(err, &pe) {
+		return
+	}
+	if pe.Position() < 0 || pe.Position() > utf8.RuneCountInString(input) {
+		t.Fatalf("Parse(%q): error position %d out of input bounds: %v", input, pe.Position(), err)
+	}
+	checkLocation(t, input, pe.Span())
+}
+
+// checkLocation fails when resolved locations of span don't match its rune position.
+func checkLocation(t *testing.T, input string, span *SourceSpan) {
+	t.Helper()
+	for _, loc := range []Location{span.Start, span.End} {
+		if loc.Offset < 0 || loc.Offset > len(input) || loc.Line < 1 || loc.Column < 1 {
+			t.Fatalf("Parse(%q): span %v has invalid location %+v", input, span, loc)
+		}
+		if runes := utf8.RuneCountInString(input[:loc.Offset]); runes != loc.Rune {
+			t.Fatalf("Parse(%q): span %v location %+v has byte offset of rune %d", input, span, loc, runes)
+		}
+		if lines := strings.Count(input[:loc.Offset], "\n") + 1; lines != loc.Line {
+			t.Fatalf("Parse(%q): span %v location %+v is on line %d", input, span, loc, lines)
+		}
+	}
+	if span.Start.Rune != span.Position || span.End.Rune != span.Position+span.Length {
+		t.Fatalf("Parse(%q): span %v doesn't match its locations %+v, %+v", input, span, span.Start, span.End)
+	}
+}
+
+// checkAttributeSpans fails when any span of the tree is not inside input.
+func checkAttributeSpans(t *testing.T, input string, attr *Attribute) {
+	t.Helper()
+	size := utf8.RuneCountInString(input)
+	var check func(span *SourceSpan)
+	check = func(span *SourceSpan) {
+		if span == nil {
+			return
+		}
+		if span.Position < 0 || span.Length < 0 || span.Position+span.Length > size {
+			t.Fatalf("Parse(%q): span %v out of input bounds (%d runes)", input, span, size)
+		}
+		checkLocation(t, input, span)
+	}
+	var walk func(attr *Attribute)
+	walk = func(attr *Attribute) {
+		check(attr.Span)
+		if attr.Value != nil {
+			check(attr.Value.Span)
+		}
+		for _, attrs := range []*Attributes{attr.Object, attr.Array} {
+			if attrs == nil {
+				continue
+			}
+			check(attrs.Span)
+			for _, child := range attrs.Attributes {
+				walk(child)
+			}
+		}
+	}
+	walk(attr)
+	for _, comment := range attr.Comments {
+		check(comment.Span)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		for _, options := range []Options{{}, {Comments: true, DedentStrings: true}} {
+			result, err := Parse(strings.NewReader(input), options)
+			if err != nil {
+				if result != nil {
+					t.Fatalf("Parse(%q) returned both result and error", input)
+				}
+				checkSpanBounds(t, input, err)
+				continue
+			}
+			if result == nil || result.Object == nil {
+				t.Fatalf("Parse(%q) returned no result", input)
+			}
+			checkAttributeSpans(t, input, result)
+			for _, printOptions := range []PrintOptions{
+				{},
+				{Layout: LayoutCompact, Quote: QuoteDouble, TrailingComma: TrailingCommaAlways},
+				{Layout: LayoutMultiline, Quote: QuoteAuto, TrailingComma: TrailingCommaMultiline},
+			} {
+				checkRoundTrip(t, result, printOptions)
+			}
+			checkJSONRoundTrip(t, result)
+
+			tree, err := ParseCST(strings.NewReader(input), options)
+			if err != nil {
+				t.Fatalf("ParseCST(%q) failed: %v", input, err)
+			}
+			if tree.String() != input {
+				t.Fatalf("ParseCST(%q).String() = %q", input, tree.String())
+			}
+		}
+	})
+}
+
+func FuzzLex(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		l := newLexer(strings.NewReader(input), Options{Comments: true})
+		size := utf8.RuneCountInString(input)
+		// every token consumes at least one rune, so input size bounds the token count
+		for i := 0; i <= size+1; i++ {
+			span, tok, _ := l.Lex()
+			if span.Position < 0 || span.Position > size {
+				t.Fatalf("Lex(%q): token %s span %v out of input bounds", input, tok, span)
+			}
+			if tok == TokenEOF || tok == TokenError {
+				return
+			}
+		}
+		t.Fatalf("Lex(%q): lexer did not reach EOF", input)
+	})
+}
@@ -0,0 +1,345 @@
+package parser
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidJSON = errors.New("invalid attribute json")
+)
+
+// keys of JSON form of attribute
+const (
+	jsonKeyName     = "name"
+	jsonKeyQuoted   = "quoted"
+	jsonKeyFlag     = "flag"
+	jsonKeyString   = "string"
+	jsonKeyNumber   = "number"
+	jsonKeyBoolean  = "boolean"
+	jsonKeyNull     = "null"
+	jsonKeyObject   = "object"
+	jsonKeyArray    = "array"
+	jsonKeyComments = "comments"
+	jsonKeyEncoding = "encoding"
+
+	// jsonEncodingBase64 is used for name and string value which are not valid UTF-8
+	jsonEncodingBase64 = "base64"
+)
+
+// ToMap converts attribute to its JSON form (map[string]any as produced by json.Unmarshal). Every attribute is
+// object with optional "name" (omitted for positional values), "quoted" (name was quoted string) and exactly one of:
+//   - "flag": true for bare flag (required), while required=true is "boolean": true
+//   - "string": string value
+//   - "number": number as written in input ("0x10", "1_000"), so no precision is lost
+//   - "boolean": bool value
+//   - "null": true for null literal
+//   - "object" and "array": list of attributes in input order, so positional values keep their place
+//
+// Name and string value which are not valid UTF-8 ('\xff') are base64 encoded and attribute has "encoding": "base64".
+// Top-level attribute holds comment texts in "comments". Spans are not part of JSON form.
+func ToMap(a *Attribute) map[string]any {
+	result := make(map[string]any)
+	encode := func(s string) string { return s }
+	if !utf8.ValidString(a.Name) || (a.Value != nil && a.Value.String != nil && !utf8.ValidString(*a.Value.String)) {
+		result[jsonKeyEncoding] = jsonEncodingBase64
+		encode = func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	}
+	if a.Name != "" || a.Quoted {
+		result[jsonKeyName] = encode(a.Name)
+	}
+	if a.Quoted {
+		result[jsonKeyQuoted] = true
+	}
+
+	switch {
+	case a.Object != nil:
+		result[jsonKeyObject] = toList(a.Object)
+	case a.Array != nil:
+		result[jsonKeyArray] = toList(a.Array)
+	case a.Flag:
+		result[jsonKeyFlag] = true
+	case a.Value != nil:
+		// same precedence as printer, identifier true is both string and boolean
+		switch v := a.Value; {
+		case v.Null:
+			result[jsonKeyNull] = true
+		case v.Boolean != nil:
+			b, _ := strconv.ParseBool(*v.Boolean)
+			result[jsonKeyBoolean] = b
+		case v.Number != nil:
+			result[jsonKeyNumber] = *v.Number
+		case v.String != nil:
+			result[jsonKeyString] = encode(*v.String)
+		default:
+			result[jsonKeyString] = ""
+		}
+	}
+
+	if len(a.Comments) > 0 {
+		comments := make([]any, 0, len(a.Comments))
+		for _, comment := range a.Comments {
+			comments = append(comments, comment.Text)
+		}
+		result[jsonKeyComments] = comments
+	}
+	return result
+}
+
+// toList converts object or array items to list of maps
+func toList(attrs *Attributes) []any {
+	result := make([]any, 0, len(attrs.Attributes))
+	for _, attr := range attrs.Attributes {
+		result = append(result, ToMap(attr))
+	}
+	return result
+}
+
+// FromMap converts JSON form of attribute (see ToMap) back to attribute. Resulting attribute is the same as the one
+// returned by Parse, except that spans are zero. Invalid input is reported as ErrInvalidJSON with path of
+// offending attribute.
+func FromMap(m map[string]any) (*Attribute, error) {
+	return fromMap(m, "$")
+}
+
+// fromMap converts single attribute, path is used in errors ($.object[1].array[0])
+func fromMap(m map[string]any, path string) (*Attribute, error) {
+	result := &Attribute{Span: newSourceSpan(0)}
+	kinds := make([]string, 0, 1)
+
+	for key, raw := range m {
+		var err error
+		switch key {
+		case jsonKeyName:
+			result.Name, err = jsonString(raw)
+		case jsonKeyQuoted:
+			result.Quoted, err = jsonBool(raw)
+		case jsonKeyComments:
+			result.Comments, err = jsonComments(raw)
+		case jsonKeyEncoding:
+			var encoding string
+			if encoding, err = jsonString(raw); err == nil && encoding != jsonEncodingBase64 {
+				err = fmt.Errorf("%w at %s: unknown encoding %q", ErrInvalidJSON, path, encoding)
+			}
+		case jsonKeyFlag, jsonKeyString, jsonKeyNumber, jsonKeyBoolean, jsonKeyNull, jsonKeyObject, jsonKeyArray:
+			kinds = append(kinds, key)
+			err = setJSONValue(result, key, raw, path)
+		default:
+			err = fmt.Errorf("%w at %s: unknown key %q", ErrInvalidJSON, path, key)
+		}
+		if err != nil {
+			if !errors.Is(err, ErrInvalidJSON) {
+				err = fmt.Errorf("%w at %s: %s %v", ErrInvalidJSON, path, key, err)
+			}
+			return nil, err
+		}
+	}
+
+	switch len(kinds) {
+	case 0:
+		return nil, fmt.Errorf("%w at %s: missing value", ErrInvalidJSON, path)
+	case 1:
+	default:
+		sort.Strings(kinds)
+		return nil, fmt.Errorf("%w at %s: multiple values %s", ErrInvalidJSON, path, strings.Join(kinds, ", "))
+	}
+
+	if _, ok := m[jsonKeyEncoding]; ok {
+		if err := decodeBase64(result); err != nil {
+			return nil, fmt.Errorf("%w at %s: %w", ErrInvalidJSON, path, err)
+		}
+	}
+
+	_, hasName := m[jsonKeyName]
+	switch {
+	case result.Flag && (!hasName || result.Quoted):
+		return nil, fmt.Errorf("%w at %s: flag must have unquoted name", ErrInvalidJSON, path)
+	case result.Quoted && !hasName:
+		return nil, fmt.Errorf("%w at %s: quoted attribute without name", ErrInvalidJSON, path)
+	case hasName && !result.Quoted:
+		// unquoted names are identifiers, dotted ones are paths same as in Parse
+		segments := strings.Split(result.Name, ".")
+		for _, segment := range segments {
+			if !isIdentifier(segment) {
+				return nil, fmt.Errorf("%w at %s: invalid name %q", ErrInvalidJSON, path, result.Name)
+			}
+		}
+		if len(segments) > 1 {
+			result.Path = segments
+		}
+	}
+
+	return result, nil
+}
+
+// setJSONValue sets value of given kind to attribute
+func setJSONValue(a *Attribute, key string, raw any, path string) error {
+	switch key {
+	case jsonKeyObject, jsonKeyArray:
+		list, ok := raw.([]any)
+		if !ok {
+			return fmt.Errorf("%w at %s: %s must be list", ErrInvalidJSON, path, key)
+		}
+		attrs := newAttributes(newSourceSpan(0))
+		for i, item := range list {
+			child, ok := item.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w at %s.%s[%d]: attribute must be object", ErrInvalidJSON, path, key, i)
+			}
+			attr, err := fromMap(child, fmt.Sprintf("%s.%s[%d]", path, key, i))
+			if err != nil {
+				return err
+			}
+			attrs.Push(attr)
+		}
+		if key == jsonKeyObject {
+			a.Object = attrs
+		} else {
+			a.Array = attrs
+		}
+		return nil
+	}
+
+	value := &Value{Span: newSourceSpan(0)}
+	a.Value = value
+	switch key {
+	case jsonKeyFlag:
+		if flag, err := jsonBool(raw); err != nil || !flag {
+			return fmt.Errorf("%w at %s: flag must be true", ErrInvalidJSON, path)
+		}
+		// same as bare flag in Parse
+		trueStr := "true"
+		value.Span = a.Span
+		value.String, value.Boolean = &trueStr, &trueStr
+		a.Flag = true
+	case jsonKeyNull:
+		if null, err := jsonBool(raw); err != nil || !null {
+			return fmt.Errorf("%w at %s: null must be true", ErrInvalidJSON, path)
+		}
+		value.Null = true
+	case jsonKeyBoolean:
+		b, err := jsonBool(raw)
+		if err != nil {
+			return err
+		}
+		// identifiers true and false are both strings and booleans
+		text := fmt.Sprint(b)
+		value.String, value.Boolean = &text, &text
+	case jsonKeyNumber:
+		text, err := jsonString(raw)
+		if err != nil {
+			return err
+		}
+		if !isNumberLiteral(text) {
+			return fmt.Errorf("%w at %s: invalid number %q", ErrInvalidJSON, path, text)
+		}
+		value.Number = &text
+	case jsonKeyString:
+		text, err := jsonString(raw)
+		if err != nil {
+			return err
+		}
+		value.String = &text
+	}
+	return nil
+}
+
+// decodeBase64 decodes name and string value of base64 encoded attribute
+func decodeBase64(a *Attribute) error {
+	name, err := base64.StdEncoding.DecodeString(a.Name)
+	if err != nil {
+		return fmt.Errorf("name %w", err)
+	}
+	a.Name = string(name)
+	if a.Value != nil && a.Value.String != nil && !a.Value.Null && a.Value.Boolean == nil && a.Value.Number == nil {
+		value, err := base64.StdEncoding.DecodeString(*a.Value.String)
+		if err != nil {
+			return fmt.Errorf("string %w", err)
+		}
+		text := string(value)
+		a.Value.String = &text
+	}
+	return nil
+}
+
+// isNumberLiteral returns whether text is single number token as lexer reads it
+func isNumberLiteral(text string) bool {
+	l := newLexer(strings.NewReader(text))
+	_, tok, val := l.Lex()
+	if tok != TokenNumber || val != text {
+		return false
+	}
+	_, tok, _ = l.Lex()
+	return tok == TokenEOF
+}
+
+func jsonString(raw any) (string, error) {
+	switch v := raw.(type) {
+	case string:
+		return v, nil
+	case json.Number:
+		return v.String(), nil
+	}
+	return "", fmt.Errorf("must be string, got %T", raw)
+}
+
+func jsonBool(raw any) (bool, error) {
+	if v, ok := raw.(bool); ok {
+		return v, nil
+	}
+	return false, fmt.Errorf("must be bool, got %T", raw)
+}
+
+func jsonComments(raw any) ([]*Comment, error) {
+	list, ok := raw.([]any)
+	if !ok {
+		return nil, fmt.Errorf("must be list, got %T", raw)
+	}
+	result := make([]*Comment, 0, len(list))
+	for _, item := range list {
+		text, err := jsonString(item)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, &Comment{Span: newSourceSpan(0), Text: text})
+	}
+	return result, nil
+}
+
+// ToJSON returns JSON encoding of ToMap(a).
+func ToJSON(a *Attribute) ([]byte, error) {
+	return json.Marshal(ToMap(a))
+}
+
+// FromJSON decodes attribute from JSON produced by ToJSON.
+func FromJSON(data []byte) (*Attribute, error) {
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidJSON, err)
+	}
+	if m == nil {
+		return nil, fmt.Errorf("%w: expected object", ErrInvalidJSON)
+	}
+	return FromMap(m)
+}
+
+// MarshalJSON implements json.Marshaler, see ToMap for format.
+func (a *Attribute) MarshalJSON() ([]byte, error) {
+	return ToJSON(a)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, see ToMap for format.
+func (a *Attribute) UnmarshalJSON(data []byte) error {
+	result, err := FromJSON(data)
+	if err != nil {
+		return err
+	}
+	*a = *result
+	return nil
+}
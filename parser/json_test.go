@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkJSONRoundTrip fails when attribute converted to JSON and back differs from original
+func checkJSONRoundTrip(t *testing.T, a *Attribute) {
+	t.Helper()
+	data, err := ToJSON(a)
+	if err != nil {
+		t.Fatalf("ToJSON failed: %v", err)
+	}
+	decoded, err := FromJSON(data)
+	if err != nil {
+		t.Fatalf("FromJSON(%s) failed: %v", data, err)
+	}
+	if !assert.Equal(t, structure(a), structure(decoded), "json: %s", data) {
+		t.FailNow()
+	}
+	if !assert.Equal(t, len(a.Comments), len(decoded.Comments), "json: %s", data) {
+		t.FailNow()
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	for _, item := range []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: `{"object":[]}`},
+		{input: "required, admin=true, off=false", expected: `{"object":[{"flag":true,"name":"required"},{"boolean":true,"name":"admin"},{"boolean":false,"name":"off"}]}`},
+		{input: "n=0x1F, big=123456789012345678901234567890, f=1_000.5, i=-Inf", expected: `{"object":[{"name":"n","number":"0x1F"},{"name":"big","number":"123456789012345678901234567890"},{"name":"f","number":"1_000.5"},{"name":"i","number":"-Inf"}]}`},
+		{input: "'first', 42, 'second'", expected: `{"object":[{"string":"first"},{"number":"42"},{"string":"second"}]}`},
+		{input: "s='true', n=null", expected: `{"object":[{"name":"s","string":"true"},{"name":"n","null":true}]}`},
+		{input: "'Content-Type'=json, db.pool.max=10", expected: `{"object":[{"name":"Content-Type","quoted":true,"string":"json"},{"name":"db.pool.max","number":"10"}]}`},
+		{input: `'\xff'='a\xfe', b='\x00'`, expected: `{"object":[{"encoding":"base64","name":"/w==","quoted":true,"string":"Yf4="},{"name":"b","string":"\u0000"}]}`},
+		{input: "span(start=0), ids[1, (a=1), []]", expected: `{"object":[{"name":"span","object":[{"name":"start","number":"0"}]},{"array":[{"number":"1"},{"object":[{"name":"a","number":"1"}]},{"array":[]}],"name":"ids"}]}`},
+	} {
+		t.Run(item.input, func(t *testing.T) {
+			data, err := ToJSON(MustParse(strings.NewReader(item.input)))
+			require.NoError(t, err)
+			assert.JSONEq(t, item.expected, string(data))
+		})
+	}
+
+	t.Run("test comments", func(t *testing.T) {
+		a := MustParse(strings.NewReader("# head\nid=1 // tail"), Options{Comments: true})
+		m := ToMap(a)
+		assert.Equal(t, []any{"# head", "// tail"}, m["comments"])
+
+		decoded, err := FromMap(m)
+		require.NoError(t, err)
+		require.Len(t, decoded.Comments, 2)
+		assert.Equal(t, "// tail", decoded.Comments[1].Text)
+	})
+}
+
+func TestFromJSON(t *testing.T) {
+	t.Run("test round trip", func(t *testing.T) {
+		for _, input := range []string{
+			"",
+			"name='user_id', required, span(start=0, end=255), tags['id', 'primary']",
+			"a=true, b=false, c='true', d=null, e=nil, f=Inf, g=NaN, h=-Inf, i=.5, j=0o755, k=1e6",
+			"'positional', 42, name=x, (a=1), [1, 2]",
+			"h('Content-Type'='json', 'x'(a=1), 'y'[1], ''=1)",
+			"db.pool.max=10, db.pool.min=1",
+			"users[(username='alice', admin), (username='bob')], rows[[1, 2], [3]]",
+			`s='\xff', '\xfe'=true, '\xfd'(a='\xfc')`,
+		} {
+			t.Run(input, func(t *testing.T) {
+				checkJSONRoundTrip(t, MustParse(strings.NewReader(input)))
+			})
+		}
+	})
+
+	t.Run("test paths", func(t *testing.T) {
+		a, err := FromJSON([]byte(`{"object":[{"name":"db.pool.max","number":"10"},{"name":"a.b","quoted":true,"number":"1"}]}`))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db", "pool", "max"}, a.Object.Attributes[0].Path)
+		assert.Nil(t, a.Object.Attributes[1].Path)
+	})
+
+	t.Run("test build", func(t *testing.T) {
+		a, err := FromJSON([]byte(`{"object":[{"name":"n","number":"0x10"},{"name":"s","string":"x"}]}`))
+		require.NoError(t, err)
+		value, err := a.Build()
+		require.NoError(t, err)
+		assert.Equal(t, map[string]any{"n": 16, "s": "x"}, value.Interface())
+	})
+
+	t.Run("test invalid", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+		}{
+			{input: `[]`, expected: "cannot unmarshal"},
+			{input: `null`, expected: "expected object"},
+			{input: `{}`, expected: "at $: missing value"},
+			{input: `{"object":[{"string":"x","number":"1"}]}`, expected: "at $.object[0]: multiple values number, string"},
+			{input: `{"object":[{"name":"x","other":1}]}`, expected: `at $.object[0]: unknown key "other"`},
+			{input: `{"object":[{"name":"a-b","number":"1"}]}`, expected: `at $.object[0]: invalid name "a-b"`},
+			{input: `{"object":[{"name":"a..b","number":"1"}]}`, expected: `invalid name "a..b"`},
+			{input: `{"object":[{"number":"0x"}]}`, expected: `at $.object[0]: invalid number "0x"`},
+			{input: `{"object":[{"number":"1 2"}]}`, expected: `invalid number "1 2"`},
+			{input: `{"object":[{"number":1}]}`, expected: "at $.object[0]: number must be string, got float64"},
+			{input: `{"object":[{"flag":true}]}`, expected: "flag must have unquoted name"},
+			{input: `{"object":[{"name":"x","quoted":true,"flag":true}]}`, expected: "flag must have unquoted name"},
+			{input: `{"object":[{"name":"x","flag":false}]}`, expected: "flag must be true"},
+			{input: `{"object":[{"quoted":true,"string":"x"}]}`, expected: "quoted attribute without name"},
+			{input: `{"array":[1]}`, expected: "at $.array[0]: attribute must be object"},
+			{input: `{"array":{}}`, expected: "array must be list"},
+			{input: `{"encoding":"hex","string":"ff"}`, expected: `unknown encoding "hex"`},
+			{input: `{"encoding":"base64","string":"!"}`, expected: "string illegal base64 data"},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				_, err := FromJSON([]byte(item.input))
+				assert.ErrorIs(t, err, ErrInvalidJSON)
+				assert.ErrorContains(t, err, item.expected)
+			})
+		}
+	})
+}
+
+func TestAttributeJSONMarshaler(t *testing.T) {
+	type stored struct {
+		Tag *Attribute `json:"tag"`
+	}
+
+	data, err := json.Marshal(stored{Tag: MustParse(strings.NewReader("id=1, required"))})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tag":{"object":[{"name":"id","number":"1"},{"name":"required","flag":true}]}}`, string(data))
+
+	var decoded stored
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, structure(MustParse(strings.NewReader("id=1, required"))), structure(decoded.Tag))
+}
@@ -6,30 +6,46 @@ import (
 	"io"
 	"strings"
 	"unicode"
+	"unicode/utf8"
 )
 
-func newLexer(reader io.Reader) *lexer {
+func newLexer(reader io.Reader, options ...Options) *lexer {
 	all, err := io.ReadAll(reader) // read all to get correct EOF position
 	if err != nil {
 		panic(err)
 	}
 	stringContent := string(all)
-	return &lexer{
+	result := &lexer{
 		content: stringContent,
 		reader:  bufio.NewReader(strings.NewReader(stringContent)),
 	}
+	if len(options) > 0 {
+		result.options = options[0]
+	}
+	return result
 }
 
 type lexer struct {
+	// pos is position in runes, offset is position in bytes
 	pos     int
+	offset  int
 	content string
 	reader  *bufio.Reader
+
+	// size of last read rune in bytes, 0 when there is nothing to unread
+	size int
+
+	options Options
+
+	// comments found so far, parser can lex same input multiple times (peek, rollback)
+	comments []*Comment
 }
 
 // Snapshot returns snapshot which can be used to "rollback to"
 func (l *lexer) Snapshot() *Snapshot {
 	return &Snapshot{
-		pos: l.pos,
+		pos:    l.pos,
+		offset: l.offset,
 	}
 }
 
@@ -42,30 +58,26 @@ func (l *lexer) Rollback(to *Snapshot) error {
 		return nil
 	}
 	l.reader = bufio.NewReader(strings.NewReader(l.content))
-	// to.pos may exceed len(content) by one when an identifier/number read
-	// consumed EOF and incremented l.pos past the end of the string. Clamp
-	// the discard amount so that bufio.Discard does not hit EOF mid-stream.
-	discardTo := to.pos
-	if discardTo > len(l.content) {
-		discardTo = len(l.content)
-	}
-	disc, err := l.reader.Discard(discardTo)
+	// pos counts runes, so we need to discard by byte offset (differs for non-ASCII input).
+	disc, err := l.reader.Discard(to.offset)
 	if err != nil {
 		return err
 	}
-	if disc != discardTo {
-		return fmt.Errorf("expected to discard: %d but %d", discardTo, disc)
+	if disc != to.offset {
+		return fmt.Errorf("expected to discard: %d but %d", to.offset, disc)
 	}
 	l.pos = to.pos
+	l.offset = to.offset
+	l.size = 0
 
 	return nil
 }
 
 func (l *lexer) Lex() (*SourceSpan, Token, string) {
 	for {
+		span := newSourceSpan(l.pos)
+		offset := l.offset
 		r, err := l.read()
-		pos := l.pos - 1
-		span := newSourceSpan(pos)
 		if err != nil {
 			if err == io.EOF {
 				return span.withLengthFromPosition(l.pos), TokenEOF, ""
@@ -87,135 +99,45 @@ func (l *lexer) Lex() (*SourceSpan, Token, string) {
 			return span.withLength(1), TokenEqual, "="
 		case ',':
 			return span.withLength(1), TokenComma, ","
-		case '"':
-			str := ""
-		outer2:
-			for {
-				r, err := l.read()
-				if err != nil {
-					if err == io.EOF {
-						return newSourceSpan(l.pos - 1), TokenError, ""
-					}
-					// this should not happen
-					return span.withLengthFromPosition(l.pos), TokenError, err.Error()
-				}
-
-				switch {
-				case r == '\\':
-					pr, errp := l.read()
-					if errp != nil {
-						if errp == io.EOF {
-							return span.withLengthFromPosition(l.pos), TokenError, str
-						}
-					}
-					switch pr {
-					case 'n':
-						str += string("\n")
-						continue outer2
-					default:
-						l.unread()
-					}
-				}
-
-				if r == '"' {
-					return span.withLengthFromPosition(l.pos), TokenString, str
-				}
-
-				str += string(r)
+		case '"', '\'':
+			if l.hasQuotes(r, 2) {
+				return l.lexMultilineString(span, r)
 			}
-
-		case '\'':
-			str := ""
-		outer:
-			for {
-				r, err := l.read()
-				if err != nil {
-					if err == io.EOF {
-						return newSourceSpan(l.pos - 1), TokenError, ""
-					}
-					// this should not happen
-					return newSourceSpan(l.pos - 1), TokenError, err.Error()
+			return l.lexString(span, r)
+		case '`':
+			return l.lexRawString(span)
+		case '#':
+			if l.options.Comments {
+				l.skipLineComment(span, offset)
+				continue
+			}
+		case '/':
+			if l.options.Comments {
+				next, err := l.read()
+				if err == nil && next == '/' {
+					l.skipLineComment(span, offset)
+					continue
 				}
-
-				switch {
-				case r == '\\':
-					pr, errp := l.read()
-					if errp != nil {
-						if errp == io.EOF {
-							return newSourceSpan(l.pos - 1), TokenError, str
-						}
-					}
-					switch pr {
-					case '\'':
-						str += string(pr)
-						continue outer
-					case '\\':
-						// TODO: iterate over this over and over until we find a quote
-						str += string(r)
-					default:
-						l.unread()
+				if err == nil && next == '*' {
+					if !l.skipBlockComment(span, offset) {
+						return span.withLengthFromPosition(l.pos), TokenError, "unterminated block comment"
 					}
-				case r == '\'':
-					return span.withLengthFromPosition(l.pos), TokenString, str
+					continue
 				}
-
-				if r == '\'' {
-					return span.withLengthFromPosition(l.pos), TokenString, str
-				}
-
-				str += string(r)
+				l.unread()
 			}
 		default:
 			if unicode.IsSpace(r) {
 				continue // nothing to do here, just move on
 			}
-			if unicode.IsDigit(r) || r == '.' || r == '-' {
-				str := string(r)
-				if r == '-' {
-					r, err := l.read()
-					if err != nil {
-						if err == io.EOF {
-							return newSourceSpan(l.pos - 1), TokenError, "found minus sign at EOF"
-						}
-						return newSourceSpan(l.pos - 1), TokenError, err.Error()
-					}
-					l.unread()
-					if !(unicode.IsDigit(r) || r == '.') {
-						return span.withLengthFromPosition(l.pos - 1), TokenString, ""
-					}
-
-					if r == '.' {
-						str += "0"
-					}
+			// dot followed by identifier separates path segments (db.pool.max), otherwise it starts number (.5)
+			if r == '.' {
+				if next, err := l.peek(); err == nil && (unicode.IsLetter(next) || next == '_') {
+					return span.withLength(1), TokenDot, "."
 				}
-
-				span = span.withLengthFromPosition(l.pos)
-				var foundDot = r == '.'
-
-				for {
-					r, err = l.read()
-					if err != nil {
-						if err == io.EOF {
-							break
-						}
-						return newSourceSpan(l.pos - 1), TokenError, err.Error()
-					}
-
-					if unicode.IsNumber(r) {
-						str += string(r)
-					} else if r == '.' {
-						if foundDot {
-							return newSourceSpan(l.pos - 1), TokenError, "found multiple dots in number"
-						}
-						foundDot = true
-						str += string(r)
-					} else {
-						l.unread()
-						break
-					}
-					span = span.withLengthFromPosition(l.pos)
-				}
-				return span, TokenNumber, str
+			}
+			if isDecimal(r) || r == '.' || r == '-' || r == '+' {
+				return l.lexNumber(span, r)
 			}
 			if unicode.IsLetter(r) || r == '_' {
 				str := string(r)
@@ -240,7 +162,412 @@ func (l *lexer) Lex() (*SourceSpan, Token, string) {
 				return span, TokenIdent, str
 			}
 		}
+
+		// characters outside of tokens (comment markers when comments are disabled end up here too) are skipped
+		// unless they are rejected
+		if !l.options.RejectStrayCharacters {
+			continue
+		}
+		return span.withLength(1), TokenError, fmt.Sprintf("unexpected character %q", r)
+	}
+}
+
+// skipLineComment skips comment until end of line (comment marker is already read), new line is not part of comment.
+func (l *lexer) skipLineComment(span *SourceSpan, offset int) {
+	for {
+		r, err := l.read()
+		if err != nil {
+			break
+		}
+		if r == '\n' {
+			l.unread()
+			break
+		}
 	}
+	l.addComment(span.withLengthFromPosition(l.pos), offset)
+}
+
+// skipBlockComment skips comment until closing */ (opening /* is already read), block comments do not nest.
+// It returns false when comment is not terminated.
+func (l *lexer) skipBlockComment(span *SourceSpan, offset int) bool {
+	star := false
+	for {
+		r, err := l.read()
+		if err != nil {
+			return false
+		}
+		if star && r == '/' {
+			l.addComment(span.withLengthFromPosition(l.pos), offset)
+			return true
+		}
+		star = r == '*'
+	}
+}
+
+// addComment records comment, comments lexed again after rollback are ignored.
+func (l *lexer) addComment(span *SourceSpan, offset int) {
+	if n := len(l.comments); n > 0 && l.comments[n-1].Span.Position >= span.Position {
+		return
+	}
+	l.comments = append(l.comments, &Comment{
+		Span: span,
+		Text: l.content[offset:l.offset],
+	})
+}
+
+// lexString lexes string enclosed in given quote (opening quote is already read).
+// Escape sequences follow Go rules, and both \' and \" are allowed regardless of quote style.
+func (l *lexer) lexString(span *SourceSpan, quote rune) (*SourceSpan, Token, string) {
+	var sb strings.Builder
+	for {
+		r, err := l.read()
+		if err != nil {
+			if err == io.EOF {
+				return newSourceSpan(l.pos), TokenError, "unterminated string"
+			}
+			// this should not happen
+			return span.withLengthFromPosition(l.pos), TokenError, err.Error()
+		}
+
+		switch r {
+		case quote:
+			return span.withLengthFromPosition(l.pos), TokenString, sb.String()
+		case '\\':
+			if errSpan, msg := l.lexEscape(&sb); errSpan != nil {
+				return errSpan, TokenError, msg
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// lexMultilineString lexes string enclosed in triple quotes (first quote is already read). New lines are kept and
+// escape sequences are processed same as in lexString.
+func (l *lexer) lexMultilineString(span *SourceSpan, quote rune) (*SourceSpan, Token, string) {
+	// read rest of opening quotes
+	l.read()
+	l.read()
+
+	var sb strings.Builder
+	for {
+		r, err := l.read()
+		if err != nil {
+			if err == io.EOF {
+				return span.withLength(3), TokenError, "unterminated multi-line string"
+			}
+			// this should not happen
+			return span.withLengthFromPosition(l.pos), TokenError, err.Error()
+		}
+
+		switch {
+		case r == quote && l.hasQuotes(quote, 2):
+			l.read()
+			l.read()
+			result := sb.String()
+			if l.options.DedentStrings {
+				result = dedent(trimLeadingNewline(result))
+			}
+			return span.withLengthFromPosition(l.pos), TokenString, result
+		case r == '\\':
+			if errSpan, msg := l.lexEscape(&sb); errSpan != nil {
+				return errSpan, TokenError, msg
+			}
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+// hasQuotes returns whether next n runes of input are given quote (quotes are ASCII, so we can compare bytes).
+func (l *lexer) hasQuotes(quote rune, n int) bool {
+	rest := l.content[l.offset:]
+	if len(rest) < n {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if rest[i] != byte(quote) {
+			return false
+		}
+	}
+	return true
+}
+
+// lexRawString lexes string enclosed in backticks (opening backtick is already read), no escapes are processed.
+func (l *lexer) lexRawString(span *SourceSpan) (*SourceSpan, Token, string) {
+	var sb strings.Builder
+	for {
+		r, err := l.read()
+		if err != nil {
+			if err == io.EOF {
+				return newSourceSpan(l.pos), TokenError, "unterminated raw string"
+			}
+			// this should not happen
+			return span.withLengthFromPosition(l.pos), TokenError, err.Error()
+		}
+		if r == '`' {
+			return span.withLengthFromPosition(l.pos), TokenString, sb.String()
+		}
+		sb.WriteRune(r)
+	}
+}
+
+// lexNumber lexes number literal (first rune is already read). It follows Go syntax for integer and floating-point
+// literals (base prefixes, fractions, exponents and underscores) with optional sign, signed Inf and NaN are numbers too
+// (bare Inf and NaN are identifiers).
+func (l *lexer) lexNumber(span *SourceSpan, r rune) (*SourceSpan, Token, string) {
+	var sb strings.Builder
+
+	if r == '-' || r == '+' {
+		sign := "minus"
+		if r == '+' {
+			sign = "plus"
+		}
+		sb.WriteRune(r)
+		next, err := l.read()
+		if err != nil {
+			if err == io.EOF {
+				return span.withLength(1), TokenError, fmt.Sprintf("found %s sign at EOF", sign)
+			}
+			return span.withLength(1), TokenError, err.Error()
+		}
+		switch {
+		case isDecimal(next):
+			r = next
+		case next == '.':
+			// -.5 is normalized to -0.5
+			sb.WriteRune('0')
+			r = next
+		case unicode.IsLetter(next):
+			// only special float values (-Inf) can follow sign
+			sb.WriteRune(next)
+			for {
+				next, err = l.read()
+				if err != nil {
+					break
+				}
+				if !unicode.IsLetter(next) {
+					l.unread()
+					break
+				}
+				sb.WriteRune(next)
+			}
+			if !isSpecialFloat(sb.String()[1:]) {
+				return span.withLengthFromPosition(l.pos), TokenError, fmt.Sprintf("invalid number %q", sb.String())
+			}
+			return span.withLengthFromPosition(l.pos), TokenNumber, sb.String()
+		default:
+			l.unread()
+			return span.withLength(1), TokenError, fmt.Sprintf("expected number after %s sign", sign)
+		}
+	}
+	sb.WriteRune(r)
+
+	// base prefix
+	base := 10
+	if r == '0' {
+		if next, err := l.read(); err == nil {
+			switch next {
+			case 'x', 'X':
+				base = 16
+			case 'o', 'O':
+				base = 8
+			case 'b', 'B':
+				base = 2
+			default:
+				l.unread()
+			}
+			if base != 10 {
+				sb.WriteRune(next)
+			}
+		}
+	}
+
+	// integer part and fraction (number can start with dot)
+	digits := 0
+	if isDecimal(r) && base == 10 {
+		digits++
+	}
+	foundDot := r == '.'
+	digits += l.readNumberDigits(&sb, base)
+	if !foundDot && (base == 10 || base == 16) {
+		if next, err := l.read(); err == nil {
+			if next == '.' {
+				foundDot = true
+				sb.WriteRune(next)
+			} else {
+				l.unread()
+			}
+		}
+	}
+	if foundDot {
+		digits += l.readNumberDigits(&sb, base)
+	}
+
+	// exponent (e for decimal, p for hexadecimal)
+	if next, err := l.read(); err == nil {
+		if base == 10 && (next == 'e' || next == 'E') || base == 16 && (next == 'p' || next == 'P') {
+			sb.WriteRune(next)
+			if next, err = l.read(); err == nil {
+				if next == '-' || next == '+' {
+					sb.WriteRune(next)
+				} else {
+					l.unread()
+				}
+			}
+			if l.readNumberDigits(&sb, 10) == 0 {
+				return span.withLengthFromPosition(l.pos), TokenError, "exponent has no digits"
+			}
+		} else {
+			l.unread()
+		}
+	}
+
+	// check what follows the number
+	if next, err := l.read(); err == nil {
+		switch {
+		case next == '.':
+			return newSourceSpan(l.pos - 1), TokenError, "found multiple dots in number"
+		case isDecimal(next) && base < 10:
+			return span.withLengthFromPosition(l.pos), TokenError, fmt.Sprintf("invalid digit %q in %s literal", next, baseName(base))
+		default:
+			l.unread()
+		}
+	}
+
+	if base != 10 && digits == 0 {
+		return span.withLengthFromPosition(l.pos), TokenError, fmt.Sprintf("%s literal has no digits", baseName(base))
+	}
+	if !underscoreOK(sb.String()) {
+		return span.withLengthFromPosition(l.pos), TokenError, "'_' must separate successive digits"
+	}
+
+	return span.withLengthFromPosition(l.pos), TokenNumber, sb.String()
+}
+
+// readNumberDigits reads digits of given base (and underscores) into sb and returns number of digits read.
+func (l *lexer) readNumberDigits(sb *strings.Builder, base int) (count int) {
+	for {
+		r, err := l.read()
+		if err != nil {
+			return count
+		}
+		if r != '_' && digitValue(r) >= base {
+			l.unread()
+			return count
+		}
+		sb.WriteRune(r)
+		if r != '_' {
+			count++
+		}
+	}
+}
+
+// lexEscape reads escape sequence (backslash is already read) and writes its value to sb.
+// On invalid escape sequence it returns span of the escape sequence and error message.
+func (l *lexer) lexEscape(sb *strings.Builder) (*SourceSpan, string) {
+	span := newSourceSpan(l.pos - 1)
+	r, err := l.read()
+	if err != nil {
+		return newSourceSpan(l.pos), "unterminated string"
+	}
+
+	switch r {
+	case 'a':
+		sb.WriteByte('\a')
+	case 'b':
+		sb.WriteByte('\b')
+	case 'f':
+		sb.WriteByte('\f')
+	case 'n':
+		sb.WriteByte('\n')
+	case 'r':
+		sb.WriteByte('\r')
+	case 't':
+		sb.WriteByte('\t')
+	case 'v':
+		sb.WriteByte('\v')
+	case '\\', '\'', '"':
+		sb.WriteRune(r)
+	case 'x':
+		value, ok := l.readDigits(16, 2, 2)
+		if !ok {
+			return span.withLengthFromPosition(l.pos), "invalid hex escape sequence"
+		}
+		sb.WriteByte(byte(value))
+	case 'u', 'U':
+		size := 4
+		if r == 'U' {
+			size = 8
+		}
+		value, ok := l.readDigits(16, size, size)
+		if !ok {
+			return span.withLengthFromPosition(l.pos), "invalid unicode escape sequence"
+		}
+		if !utf8.ValidRune(rune(value)) {
+			return span.withLengthFromPosition(l.pos), "escape sequence is invalid unicode code point"
+		}
+		sb.WriteRune(rune(value))
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		// octal escape, unlike Go we allow shorter forms such as \0
+		l.unread()
+		value, _ := l.readDigits(8, 1, 3)
+		if value > 255 {
+			return span.withLengthFromPosition(l.pos), "octal escape value > 255"
+		}
+		sb.WriteByte(byte(value))
+	default:
+		return span.withLengthFromPosition(l.pos), fmt.Sprintf("unknown escape sequence \\%c", r)
+	}
+	return nil, ""
+}
+
+// readDigits reads between minimum and maximum digits in given base and returns their value.
+func (l *lexer) readDigits(base, minimum, maximum int) (value int, ok bool) {
+	for i := 0; i < maximum; i++ {
+		r, err := l.read()
+		if err != nil {
+			return value, i >= minimum
+		}
+		digit := digitValue(r)
+		if digit >= base {
+			l.unread()
+			return value, i >= minimum
+		}
+		value = value*base + digit
+	}
+	return value, true
+}
+
+// isDecimal returns whether rune is ASCII decimal digit.
+func isDecimal(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+// baseName returns name of number literal base for error messages.
+func baseName(base int) string {
+	switch base {
+	case 2:
+		return "binary"
+	case 8:
+		return "octal"
+	case 16:
+		return "hexadecimal"
+	}
+	return "decimal"
+}
+
+// digitValue returns value of hexadecimal digit, or 16 when rune is not a digit.
+func digitValue(r rune) int {
+	switch {
+	case '0' <= r && r <= '9':
+		return int(r - '0')
+	case 'a' <= r && r <= 'f':
+		return int(r - 'a' + 10)
+	case 'A' <= r && r <= 'F':
+		return int(r - 'A' + 10)
+	}
+	return 16
 }
 
 func (l *lexer) peek() (rune, error) {
@@ -250,19 +577,33 @@ func (l *lexer) peek() (rune, error) {
 }
 
 func (l *lexer) read() (rune, error) {
-	r, _, err := l.reader.ReadRune()
+	r, size, err := l.reader.ReadRune()
+	if err != nil {
+		// position stays at the end of input, so there is nothing to unread
+		l.size = 0
+		return r, err
+	}
 	l.pos++
-	return r, err
+	l.offset += size
+	l.size = size
+	return r, nil
 }
 
+// unread unreads last read rune, it supports only single step back (same as bufio.Reader).
 func (l *lexer) unread() {
+	if l.size == 0 {
+		return
+	}
 	_ = l.reader.UnreadRune()
 	l.pos--
+	l.offset -= l.size
+	l.size = 0
 }
 
 // Snapshot taken in time
 type Snapshot struct {
-	pos int
+	pos    int
+	offset int
 }
 
 func (s *Snapshot) Rollback(p *parser) error {
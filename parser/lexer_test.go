@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"fmt"
 	"strings"
 	"testing"
 
@@ -24,9 +25,9 @@ func TestLexer(t *testing.T) {
 			{inp: "[", tok: TokenOpenSquareBracket, val: "[", length: 1},
 			{inp: "]", tok: TokenCloseSquareBracket, val: "]", length: 1},
 			{inp: "'hello world'", tok: TokenString, val: "hello world"},
-			{inp: "'hello world", tok: TokenError, pos: 12},
+			{inp: "'hello world", tok: TokenError, val: "unterminated string", pos: 12},
 			{inp: `'hello \' world'`, tok: TokenString, val: "hello ' world"},
-			{inp: `'hello \\ world'`, tok: TokenString, val: "hello \\\\ world"},
+			{inp: `'hello \\ world'`, tok: TokenString, val: "hello \\ world"},
 			{inp: "1234", tok: TokenNumber, val: "1234"},
 			{inp: "1234xxx", tok: TokenNumber, val: "1234"},
 			{inp: "1234.566", tok: TokenNumber, val: "1234.566"},
@@ -51,7 +52,7 @@ func TestLexer(t *testing.T) {
 			pos int
 		}{
 			{inp: "'hello world'", tok: TokenString, val: "hello world"},
-			{inp: "'hello world", tok: TokenError, pos: 12},
+			{inp: "'hello world", tok: TokenError, val: "unterminated string", pos: 12},
 			{inp: `'hello \' world'`, tok: TokenString, val: `hello ' world`},
 			{inp: `"hello world"`, tok: TokenString, val: `hello world`},
 		}
@@ -180,6 +181,7 @@ func TestTokenString(t *testing.T) {
 		{TokenOpenSquareBracket, "OPEN_SQUARE_BRACKET"},
 		{TokenCloseSquareBracket, "CLOSE_SQUARE_BRACKET"},
 		{TokenError, "ERROR"},
+		{TokenDot, "DOT"},
 		{Token(999), "UNKNOWN"},
 	}
 	for _, c := range cases {
@@ -202,6 +204,9 @@ func TestLexerExtended(t *testing.T) {
 			{inp: ")", tok: TokenCloseBracket, val: ")", pos: 0, length: 1},
 			{inp: "[", tok: TokenOpenSquareBracket, val: "[", pos: 0, length: 1},
 			{inp: "]", tok: TokenCloseSquareBracket, val: "]", pos: 0, length: 1},
+			{inp: ".a", tok: TokenDot, val: ".", pos: 0, length: 1},
+			{inp: "._", tok: TokenDot, val: ".", pos: 0, length: 1},
+			{inp: ".5", tok: TokenNumber, val: ".5", pos: 0, length: 2},
 		}
 		for _, c := range cases {
 			span, tok, val := newLexer(strings.NewReader(c.inp)).Lex()
@@ -240,10 +245,9 @@ func TestLexerExtended(t *testing.T) {
 			{inp: `'hello'`, tok: TokenString, val: "hello"},
 			{inp: `'  spaces  '`, tok: TokenString, val: "  spaces  "},
 			{inp: `'hello \' world'`, tok: TokenString, val: "hello ' world"},
-			// documented behavior: \\ in single-quoted strings produces two backslashes
-			{inp: `'hello \\ world'`, tok: TokenString, val: "hello \\\\ world"},
-			// documented behavior: unknown escape sequence \n passes backslash through literally
-			{inp: `'unknown \n escape'`, tok: TokenString, val: "unknown \\n escape"},
+			{inp: `'hello \\ world'`, tok: TokenString, val: "hello \\ world"},
+			{inp: `'new \n line'`, tok: TokenString, val: "new \n line"},
+			{inp: `'double \" quote'`, tok: TokenString, val: `double " quote`},
 			{inp: `'unterminated`, isError: true},
 		}
 		for _, c := range cases {
@@ -267,10 +271,9 @@ func TestLexerExtended(t *testing.T) {
 			{inp: `""`, tok: TokenString, val: ""},
 			{inp: `"hello"`, tok: TokenString, val: "hello"},
 			{inp: `"  spaces  "`, tok: TokenString, val: "  spaces  "},
-			// \n is the only escape sequence recognised in double-quoted strings
 			{inp: `"hello\nworld"`, tok: TokenString, val: "hello\nworld"},
-			// documented behavior: unknown escape \t passes backslash through literally
-			{inp: `"unknown \t escape"`, tok: TokenString, val: "unknown \\t escape"},
+			{inp: `"tab \t escape"`, tok: TokenString, val: "tab \t escape"},
+			{inp: `"single \' quote"`, tok: TokenString, val: `single ' quote`},
 			{inp: `"unterminated`, isError: true},
 		}
 		for _, c := range cases {
@@ -284,6 +287,203 @@ func TestLexerExtended(t *testing.T) {
 		}
 	})
 
+	t.Run("escape sequences", func(t *testing.T) {
+		cases := []struct {
+			inp string
+			val string
+		}{
+			{inp: `"\a\b\f\n\r\t\v"`, val: "\a\b\f\n\r\t\v"},
+			{inp: `'\a\b\f\n\r\t\v'`, val: "\a\b\f\n\r\t\v"},
+			{inp: `"\\"`, val: `\`},
+			{inp: `"\""`, val: `"`},
+			{inp: `'\''`, val: `'`},
+			{inp: `"\x41\x62"`, val: "Ab"},
+			{inp: `'\x41'`, val: "A"},
+			{inp: `"\u00e9"`, val: "é"},
+			{inp: `"\U0001F600"`, val: "\U0001F600"},
+			{inp: `"\0"`, val: "\x00"},
+			{inp: `"\101"`, val: "A"},
+			{inp: `"\07x"`, val: "\x07x"},
+			{inp: `'C:\\Windows\\System32'`, val: `C:\Windows\System32`},
+		}
+		for _, c := range cases {
+			_, tok, val := newLexer(strings.NewReader(c.inp)).Lex()
+			assert.Equal(t, TokenString, tok, "inp: %q", c.inp)
+			assert.Equal(t, c.val, val, "inp: %q", c.inp)
+		}
+	})
+
+	t.Run("invalid escape sequences", func(t *testing.T) {
+		cases := []struct {
+			inp         string
+			pos         int
+			length      int
+			errContains string
+		}{
+			{inp: `"\q"`, pos: 1, length: 2, errContains: `unknown escape sequence \q`},
+			{inp: `'abc \d'`, pos: 5, length: 2, errContains: `unknown escape sequence \d`},
+			{inp: `"\xZZ"`, pos: 1, length: 2, errContains: "invalid hex escape sequence"},
+			{inp: `"\x4"`, pos: 1, length: 3, errContains: "invalid hex escape sequence"},
+			{inp: `"\u12"`, pos: 1, length: 4, errContains: "invalid unicode escape sequence"},
+			{inp: `"\UFFFFFFFF"`, pos: 1, length: 10, errContains: "invalid unicode code point"},
+			{inp: `"\777"`, pos: 1, length: 4, errContains: "octal escape value > 255"},
+			{inp: `"\`, pos: 2, errContains: "unterminated string"},
+		}
+		for _, c := range cases {
+			span, tok, val := newLexer(strings.NewReader(c.inp)).Lex()
+			assert.Equal(t, TokenError, tok, "inp: %q", c.inp)
+			assert.Contains(t, val, c.errContains, "inp: %q", c.inp)
+			assert.Equal(t, c.pos, span.Position, "inp: %q", c.inp)
+			assert.Equal(t, c.length, span.Length, "inp: %q", c.inp)
+		}
+	})
+
+	t.Run("raw strings", func(t *testing.T) {
+		cases := []struct {
+			inp     string
+			val     string
+			isError bool
+		}{
+			{inp: "``", val: ""},
+			{inp: "`^\\d+\\.\\d+$`", val: `^\d+\.\d+$`},
+			{inp: "`C:\\Windows\\System32`", val: `C:\Windows\System32`},
+			{inp: "`it's \"quoted\"`", val: `it's "quoted"`},
+			{inp: "`multi\nline`", val: "multi\nline"},
+			{inp: "`unterminated", isError: true},
+		}
+		for _, c := range cases {
+			_, tok, val := newLexer(strings.NewReader(c.inp)).Lex()
+			if c.isError {
+				assert.Equal(t, TokenError, tok, "inp: %q", c.inp)
+				assert.Equal(t, "unterminated raw string", val, "inp: %q", c.inp)
+			} else {
+				assert.Equal(t, TokenString, tok, "inp: %q", c.inp)
+				assert.Equal(t, c.val, val, "inp: %q", c.inp)
+			}
+		}
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		cases := []struct {
+			inp      string
+			tokens   []Token
+			comments []string
+		}{
+			{inp: "# comment", tokens: []Token{TokenEOF}, comments: []string{"# comment"}},
+			{inp: "a # comment\nb", tokens: []Token{TokenIdent, TokenIdent, TokenEOF}, comments: []string{"# comment"}},
+			{inp: "a // comment\r\nb", tokens: []Token{TokenIdent, TokenIdent, TokenEOF}, comments: []string{"// comment\r"}},
+			{inp: "a/* x\n * y */=1", tokens: []Token{TokenIdent, TokenEqual, TokenNumber, TokenEOF}, comments: []string{"/* x\n * y */"}},
+			{inp: "/**/a/***/", tokens: []Token{TokenIdent, TokenEOF}, comments: []string{"/**/", "/***/"}},
+			{inp: "'# no comment'", tokens: []Token{TokenString, TokenEOF}},
+		}
+		for _, c := range cases {
+			l := newLexer(strings.NewReader(c.inp), Options{Comments: true})
+			var tokens []Token
+			for {
+				_, tok, val := l.Lex()
+				require.NotEqual(t, TokenError, tok, "inp: %q, val: %q", c.inp, val)
+				tokens = append(tokens, tok)
+				if tok == TokenEOF {
+					break
+				}
+			}
+			assert.Equal(t, c.tokens, tokens, "inp: %q", c.inp)
+			var comments []string
+			for _, comment := range l.comments {
+				comments = append(comments, comment.Text)
+			}
+			assert.Equal(t, c.comments, comments, "inp: %q", c.inp)
+		}
+	})
+
+	t.Run("comments disabled", func(t *testing.T) {
+		for _, inp := range []string{"# comment", "// comment", "/* comment */"} {
+			span, tok, val := newLexer(strings.NewReader(inp), Options{RejectStrayCharacters: true}).Lex()
+			assert.Equal(t, TokenError, tok, "inp: %q", inp)
+			assert.Equal(t, fmt.Sprintf("unexpected character %q", inp[0]), val, "inp: %q", inp)
+			assert.Equal(t, newSourceSpan(0, 1), span, "inp: %q", inp)
+		}
+	})
+
+	t.Run("stray characters", func(t *testing.T) {
+		for _, item := range []struct {
+			inp      string
+			expected []lexToken
+		}{
+			{inp: "!a", expected: []lexToken{{TokenIdent, "a"}, {TokenEOF, ""}}},
+			{inp: "a=1 # x", expected: []lexToken{{TokenIdent, "a"}, {TokenEqual, "="}, {TokenNumber, "1"}, {TokenIdent, "x"}, {TokenEOF, ""}}},
+			{inp: "@ / *", expected: []lexToken{{TokenEOF, ""}}},
+		} {
+			// skipped by default
+			assert.Equal(t, item.expected, lexAll(item.inp), "inp: %q", item.inp)
+
+			l := newLexer(strings.NewReader(item.inp), Options{RejectStrayCharacters: true})
+			for {
+				_, tok, val := l.Lex()
+				if tok == TokenError {
+					assert.Contains(t, val, "unexpected character", "inp: %q", item.inp)
+					break
+				}
+				require.NotEqual(t, TokenEOF, tok, "inp: %q", item.inp)
+			}
+		}
+	})
+
+	t.Run("unterminated block comment", func(t *testing.T) {
+		for _, inp := range []string{"/*", "a /* comment", "/* comment *"} {
+			l := newLexer(strings.NewReader(inp), Options{Comments: true})
+			span, tok, val := l.Lex()
+			for tok == TokenIdent {
+				span, tok, val = l.Lex()
+			}
+			assert.Equal(t, TokenError, tok, "inp: %q", inp)
+			assert.Equal(t, "unterminated block comment", val, "inp: %q", inp)
+			assert.Equal(t, strings.Index(inp, "/*"), span.Position, "inp: %q", inp)
+		}
+	})
+
+	t.Run("multi-line strings", func(t *testing.T) {
+		cases := []struct {
+			inp     string
+			val     string
+			dedent  string
+			isError bool
+		}{
+			{inp: "''''''", val: "", dedent: ""},
+			{inp: "'''a\nb'''", val: "a\nb", dedent: "a\nb"},
+			{inp: `"""it's "quoted" """`, val: `it's "quoted" `, dedent: `it's "quoted" `},
+			{inp: `'''tab\t\''''`, val: "tab\t'", dedent: "tab\t'"},
+			{inp: "'''\n    SELECT *\n      FROM t\n    '''", val: "\n    SELECT *\n      FROM t\n    ", dedent: "SELECT *\n  FROM t\n"},
+			{inp: "'''\r\n  a\r\n  '''", val: "\r\n  a\r\n  ", dedent: "a\r\n"},
+			{inp: "'''unterminated''", isError: true},
+			{inp: "'''", isError: true},
+		}
+		for _, c := range cases {
+			for _, options := range []Options{{}, {DedentStrings: true}} {
+				span, tok, val := newLexer(strings.NewReader(c.inp), options).Lex()
+				if c.isError {
+					assert.Equal(t, TokenError, tok, "inp: %q", c.inp)
+					assert.Equal(t, "unterminated multi-line string", val, "inp: %q", c.inp)
+					assert.Equal(t, newSourceSpan(0, 3), span, "inp: %q", c.inp)
+					continue
+				}
+				expected := c.val
+				if options.DedentStrings {
+					expected = c.dedent
+				}
+				assert.Equal(t, TokenString, tok, "inp: %q", c.inp)
+				assert.Equal(t, expected, val, "inp: %q", c.inp)
+				// all inputs are ASCII, so span length equals input length
+				assert.Equal(t, newSourceSpan(0, len(c.inp)), span, "inp: %q", c.inp)
+			}
+		}
+
+		// empty single-quoted string is not start of multi-line string
+		_, tok, val := newLexer(strings.NewReader("'', 'a'")).Lex()
+		assert.Equal(t, TokenString, tok)
+		assert.Equal(t, "", val)
+	})
+
 	t.Run("numbers edge cases", func(t *testing.T) {
 		cases := []struct {
 			inp         string
@@ -299,8 +499,9 @@ func TestLexerExtended(t *testing.T) {
 			{inp: ".", tok: TokenNumber, val: "."},
 			// documented behavior: bare minus at EOF produces a TokenError
 			{inp: "-", errContains: "found minus sign at EOF"},
-			// documented behavior: minus before a non-digit produces an empty TokenString (minus vanishes)
-			{inp: "-x", tok: TokenString, val: ""},
+			{inp: "+", errContains: "found plus sign at EOF"},
+			{inp: "-x", errContains: `invalid number "-x"`},
+			{inp: "- 1", errContains: "expected number after minus sign"},
 		}
 		for _, c := range cases {
 			_, tok, val := newLexer(strings.NewReader(c.inp)).Lex()
@@ -314,6 +515,47 @@ func TestLexerExtended(t *testing.T) {
 		}
 	})
 
+	t.Run("number literals", func(t *testing.T) {
+		cases := []struct {
+			inp         string
+			val         string
+			errContains string
+		}{
+			{inp: "+5", val: "+5"},
+			{inp: "1e6", val: "1e6"},
+			{inp: "1E-6", val: "1E-6"},
+			{inp: "2.5e+3", val: "2.5e+3"},
+			{inp: ".5e1", val: ".5e1"},
+			{inp: "0xFF", val: "0xFF"},
+			{inp: "0Xdead_beef", val: "0Xdead_beef"},
+			{inp: "0x1.8p1", val: "0x1.8p1"},
+			{inp: "0o755", val: "0o755"},
+			{inp: "0b1010", val: "0b1010"},
+			{inp: "1_000_000", val: "1_000_000"},
+			{inp: "0755", val: "0755"},
+			{inp: "-Inf", val: "-Inf"},
+			{inp: "+Inf", val: "+Inf"},
+			{inp: "1e6x", val: "1e6"},
+			{inp: "0x", errContains: "hexadecimal literal has no digits"},
+			{inp: "0b102", errContains: `invalid digit '2' in binary literal`},
+			{inp: "0o8", errContains: `invalid digit '8' in octal literal`},
+			{inp: "1e", errContains: "exponent has no digits"},
+			{inp: "1__0", errContains: "'_' must separate successive digits"},
+			{inp: "1_", errContains: "'_' must separate successive digits"},
+			{inp: "-Infx", errContains: `invalid number "-Infx"`},
+		}
+		for _, c := range cases {
+			_, tok, val := newLexer(strings.NewReader(c.inp)).Lex()
+			if c.errContains != "" {
+				assert.Equal(t, TokenError, tok, "inp: %q", c.inp)
+				assert.Contains(t, val, c.errContains, "inp: %q", c.inp)
+			} else {
+				assert.Equal(t, TokenNumber, tok, "inp: %q", c.inp)
+				assert.Equal(t, c.val, val, "inp: %q", c.inp)
+			}
+		}
+	})
+
 	t.Run("identifiers edge cases", func(t *testing.T) {
 		cases := []struct {
 			inp string
@@ -463,6 +705,46 @@ func TestLexerSnapshotRollback(t *testing.T) {
 		assert.Equal(t, "hello", val)
 	})
 
+	t.Run("rollback of non-ASCII input", func(t *testing.T) {
+		for _, item := range []struct {
+			inp  string
+			skip int
+		}{
+			{inp: "žluť=kůň, b='ß'", skip: 1},
+			{inp: "名前='値', x=1", skip: 2},
+			{inp: "a='😀😀', b=ü", skip: 3},
+			{inp: "ä[ö, ü]", skip: 2},
+		} {
+			t.Run(item.inp, func(t *testing.T) {
+				l := newLexer(strings.NewReader(item.inp))
+				for range item.skip {
+					l.Lex()
+				}
+				snap := l.Snapshot()
+
+				type lexed struct {
+					pos int
+					tok Token
+					val string
+				}
+				lexRest := func() []lexed {
+					var result []lexed
+					for {
+						span, tok, val := l.Lex()
+						result = append(result, lexed{span.Position, tok, val})
+						if tok == TokenEOF || tok == TokenError {
+							return result
+						}
+					}
+				}
+				first := lexRest()
+				require.NoError(t, l.Rollback(snap))
+				assert.Equal(t, first, lexRest())
+				assert.NotEqual(t, TokenError, first[len(first)-1].tok)
+			})
+		}
+	})
+
 	t.Run("full sequence survives multiple rollbacks", func(t *testing.T) {
 		l := newLexer(strings.NewReader("a=b"))
 		snap := l.Snapshot()
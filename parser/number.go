@@ -0,0 +1,182 @@
+package parser
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ParseInt parses number literal into signed integer of given bit size.
+// Literal follows Go syntax: optional sign, base prefixes 0x, 0o and 0b, and underscores between digits.
+// Unlike Go, leading zero without prefix (0755) is decimal, use 0o755 for octal.
+func ParseInt(literal string, bitSize int) (int64, error) {
+	base, err := numberBase(literal)
+	if err != nil {
+		return 0, err
+	}
+	if base == 10 {
+		literal = strings.ReplaceAll(literal, "_", "")
+	}
+	return strconv.ParseInt(literal, base, bitSize)
+}
+
+// ParseUint parses number literal into unsigned integer of given bit size, see ParseInt for supported syntax.
+func ParseUint(literal string, bitSize int) (uint64, error) {
+	base, err := numberBase(literal)
+	if err != nil {
+		return 0, err
+	}
+	if base == 10 {
+		literal = strings.ReplaceAll(literal, "_", "")
+	}
+	// strconv.ParseUint does not accept plus sign
+	if strings.HasPrefix(literal, "+") {
+		literal = literal[1:]
+	}
+	return strconv.ParseUint(literal, base, bitSize)
+}
+
+// ParseFloat parses number literal into float of given bit size.
+// It supports Go floating-point syntax (decimal and hexadecimal, exponents, underscores), integer
+// literals with base prefixes and special values Inf and NaN (also signed, attribute strings read only signed
+// +Inf, -Inf, +NaN and -NaN as numbers).
+func ParseFloat(literal string, bitSize int) (float64, error) {
+	if literal == "+NaN" || literal == "-NaN" {
+		return math.NaN(), nil
+	}
+	result, err := strconv.ParseFloat(literal, bitSize)
+	if err == nil || !hasBasePrefix(literal) {
+		return result, err
+	}
+
+	// strconv.ParseFloat does not accept 0o and 0b prefixes, and hexadecimal literals need exponent
+	value, intErr := ParseInt(literal, 64)
+	if intErr != nil {
+		return result, err
+	}
+	return float64(value), nil
+}
+
+// ParseBigInt parses integer literal of any size, see ParseInt for supported syntax.
+func ParseBigInt(literal string) (*big.Int, error) {
+	base, err := numberBase(literal)
+	if err != nil {
+		return nil, err
+	}
+	digits := literal
+	if base == 10 {
+		digits = strings.ReplaceAll(literal, "_", "")
+	}
+	result, ok := new(big.Int).SetString(digits, base)
+	if !ok {
+		return nil, &strconv.NumError{Func: "ParseBigInt", Num: literal, Err: strconv.ErrSyntax}
+	}
+	return result, nil
+}
+
+// ParseBigFloat parses number literal into big.Float with given precision in bits, see ParseFloat for supported
+// syntax. Precision 0 means precision high enough for all digits of literal (at least 64). NaN is not supported
+// by big.Float.
+func ParseBigFloat(literal string, prec uint) (*big.Float, error) {
+	if prec == 0 {
+		// every digit needs at most 4 bits (hexadecimal), decimal digits need less
+		prec = max(64, uint(len(literal))*4)
+	}
+	if !underscoreOK(literal) {
+		return nil, &strconv.NumError{Func: "ParseBigFloat", Num: literal, Err: strconv.ErrSyntax}
+	}
+	result, _, err := big.ParseFloat(literal, 0, prec, big.ToNearestEven)
+	if err != nil {
+		return nil, &strconv.NumError{Func: "ParseBigFloat", Num: literal, Err: strconv.ErrSyntax}
+	}
+	return result, nil
+}
+
+// ParseBigRat parses number literal into exact rational number, see ParseFloat for supported syntax.
+// Special values Inf and NaN are not supported.
+func ParseBigRat(literal string) (*big.Rat, error) {
+	if value, err := ParseBigInt(literal); err == nil {
+		return new(big.Rat).SetInt(value), nil
+	}
+	// big.Rat accepts also fractions (1/3) which are not number literals
+	if !underscoreOK(literal) || strings.Contains(literal, "/") {
+		return nil, &strconv.NumError{Func: "ParseBigRat", Num: literal, Err: strconv.ErrSyntax}
+	}
+	result, ok := new(big.Rat).SetString(literal)
+	if !ok {
+		return nil, &strconv.NumError{Func: "ParseBigRat", Num: literal, Err: strconv.ErrSyntax}
+	}
+	return result, nil
+}
+
+// numberBase returns base for strconv to parse integer literal (0 means strconv reads prefix itself).
+func numberBase(literal string) (int, error) {
+	if !underscoreOK(literal) {
+		return 0, &strconv.NumError{Func: "ParseInt", Num: literal, Err: strconv.ErrSyntax}
+	}
+	if hasBasePrefix(literal) {
+		return 0, nil
+	}
+	return 10, nil
+}
+
+// hasBasePrefix returns whether literal (with optional sign) starts with 0x, 0o or 0b.
+func hasBasePrefix(literal string) bool {
+	literal = strings.TrimLeft(literal, "+-")
+	if len(literal) < 2 || literal[0] != '0' {
+		return false
+	}
+	switch literal[1] {
+	case 'x', 'X', 'o', 'O', 'b', 'B':
+		return true
+	}
+	return false
+}
+
+// underscoreOK reports whether underscores in literal appear only between digits
+// or between base prefix and digit (same rules as Go).
+func underscoreOK(literal string) bool {
+	if len(literal) > 0 && (literal[0] == '+' || literal[0] == '-') {
+		literal = literal[1:]
+	}
+
+	// saw is class of last character: '^' start, '0' digit or base prefix, '_' underscore, '!' anything else
+	saw := '^'
+	i := 0
+	hex := false
+	if hasBasePrefix(literal) {
+		i = 2
+		saw = '0'
+		hex = literal[1] == 'x' || literal[1] == 'X'
+	}
+
+	for ; i < len(literal); i++ {
+		c := literal[i]
+		if '0' <= c && c <= '9' || hex && digitValue(rune(c)) < 16 {
+			saw = '0'
+			continue
+		}
+		if c == '_' {
+			if saw != '0' {
+				return false
+			}
+			saw = '_'
+			continue
+		}
+		if saw == '_' {
+			return false
+		}
+		saw = '!'
+	}
+	return saw != '_'
+}
+
+// isSpecialFloat returns whether ident is special float value accepted as number after sign (-Inf, +NaN).
+func isSpecialFloat(ident string) bool {
+	switch ident {
+	case "Inf", "NaN":
+		return true
+	}
+	return false
+}
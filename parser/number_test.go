@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInt(t *testing.T) {
+	for _, item := range []struct {
+		literal  string
+		bitSize  int
+		expected int64
+		err      error
+	}{
+		{literal: "42", bitSize: 64, expected: 42},
+		{literal: "+42", bitSize: 64, expected: 42},
+		{literal: "-42", bitSize: 64, expected: -42},
+		{literal: "0755", bitSize: 64, expected: 755},
+		{literal: "0o755", bitSize: 64, expected: 0o755},
+		{literal: "0xFF", bitSize: 64, expected: 255},
+		{literal: "-0x_FF", bitSize: 64, expected: -255},
+		{literal: "0b1010", bitSize: 64, expected: 10},
+		{literal: "1_000_000", bitSize: 64, expected: 1000000},
+		{literal: "127", bitSize: 8, expected: 127},
+		{literal: "128", bitSize: 8, err: strconv.ErrRange},
+		{literal: "1__0", bitSize: 64, err: strconv.ErrSyntax},
+		{literal: "_1", bitSize: 64, err: strconv.ErrSyntax},
+		{literal: "1.5", bitSize: 64, err: strconv.ErrSyntax},
+	} {
+		value, err := ParseInt(item.literal, item.bitSize)
+		if item.err != nil {
+			assert.ErrorIs(t, err, item.err, "literal: %q", item.literal)
+		} else {
+			assert.NoError(t, err, "literal: %q", item.literal)
+			assert.Equal(t, item.expected, value, "literal: %q", item.literal)
+		}
+	}
+}
+
+func TestParseUint(t *testing.T) {
+	for _, item := range []struct {
+		literal  string
+		bitSize  int
+		expected uint64
+		err      error
+	}{
+		{literal: "42", bitSize: 64, expected: 42},
+		{literal: "+42", bitSize: 64, expected: 42},
+		{literal: "0xFFFF", bitSize: 16, expected: 0xFFFF},
+		{literal: "0x1_0000", bitSize: 16, err: strconv.ErrRange},
+		{literal: "-1", bitSize: 64, err: strconv.ErrSyntax},
+	} {
+		value, err := ParseUint(item.literal, item.bitSize)
+		if item.err != nil {
+			assert.ErrorIs(t, err, item.err, "literal: %q", item.literal)
+		} else {
+			assert.NoError(t, err, "literal: %q", item.literal)
+			assert.Equal(t, item.expected, value, "literal: %q", item.literal)
+		}
+	}
+}
+
+func TestParseFloat(t *testing.T) {
+	for _, item := range []struct {
+		literal  string
+		expected float64
+		err      error
+	}{
+		{literal: "1.5", expected: 1.5},
+		{literal: "+1.5", expected: 1.5},
+		{literal: ".5", expected: 0.5},
+		{literal: "1e6", expected: 1e6},
+		{literal: "1_000.5", expected: 1000.5},
+		{literal: "0x1p-2", expected: 0.25},
+		{literal: "0xFF", expected: 255},
+		{literal: "0o17", expected: 15},
+		{literal: "0b11", expected: 3},
+		{literal: "Inf", expected: math.Inf(1)},
+		{literal: "-Inf", expected: math.Inf(-1)},
+		{literal: "1e400", err: strconv.ErrRange},
+		{literal: "1__0", err: strconv.ErrSyntax},
+	} {
+		value, err := ParseFloat(item.literal, 64)
+		if item.err != nil {
+			assert.ErrorIs(t, err, item.err, "literal: %q", item.literal)
+		} else {
+			assert.NoError(t, err, "literal: %q", item.literal)
+			assert.Equal(t, item.expected, value, "literal: %q", item.literal)
+		}
+	}
+
+	t.Run("NaN", func(t *testing.T) {
+		for _, literal := range []string{"NaN", "+NaN", "-NaN"} {
+			value, err := ParseFloat(literal, 64)
+			assert.NoError(t, err, literal)
+			assert.True(t, math.IsNaN(value), literal)
+		}
+	})
+}
+
+func TestParseBig(t *testing.T) {
+	t.Run("test int", func(t *testing.T) {
+		for _, item := range []struct {
+			literal  string
+			expected string
+		}{
+			{literal: "42", expected: "42"},
+			{literal: "0755", expected: "755"},
+			{literal: "-0x_FF", expected: "-255"},
+			{literal: "+0b1010", expected: "10"},
+			{literal: "123_456_789_012_345_678_901_234_567_890", expected: "123456789012345678901234567890"},
+		} {
+			value, err := ParseBigInt(item.literal)
+			assert.NoError(t, err, "literal: %q", item.literal)
+			assert.Equal(t, item.expected, value.String(), "literal: %q", item.literal)
+		}
+		for _, literal := range []string{"1.5", "1__0", "Inf", "0x", ""} {
+			_, err := ParseBigInt(literal)
+			assert.ErrorIs(t, err, strconv.ErrSyntax, "literal: %q", literal)
+		}
+	})
+
+	t.Run("test float", func(t *testing.T) {
+		for _, item := range []struct {
+			literal  string
+			expected string
+		}{
+			{literal: "1.5", expected: "1.5"},
+			{literal: "0755", expected: "755"},
+			{literal: "0x1.8p1", expected: "3"},
+			{literal: "1_000.25", expected: "1000.25"},
+			{literal: "-Inf", expected: "-Inf"},
+			{literal: "123456789012345678901234567890.5", expected: "123456789012345678901234567890.5"},
+		} {
+			value, err := ParseBigFloat(item.literal, 0)
+			assert.NoError(t, err, "literal: %q", item.literal)
+			assert.Equal(t, item.expected, value.Text('f', -1), "literal: %q", item.literal)
+		}
+		value, err := ParseBigFloat("0.1", 24)
+		assert.NoError(t, err)
+		assert.Equal(t, uint(24), value.Prec())
+
+		for _, literal := range []string{"NaN", "1__0", "1.5.5", "x"} {
+			_, err := ParseBigFloat(literal, 0)
+			assert.ErrorIs(t, err, strconv.ErrSyntax, "literal: %q", literal)
+		}
+	})
+
+	t.Run("test rat", func(t *testing.T) {
+		for _, item := range []struct {
+			literal  string
+			expected string
+		}{
+			{literal: "19.99", expected: "1999/100"},
+			{literal: "0755", expected: "755"},
+			{literal: "0x10", expected: "16"},
+			{literal: "1e-3", expected: "1/1000"},
+			{literal: "-1_000.5", expected: "-2001/2"},
+		} {
+			value, err := ParseBigRat(item.literal)
+			assert.NoError(t, err, "literal: %q", item.literal)
+			assert.Equal(t, item.expected, value.RatString(), "literal: %q", item.literal)
+		}
+		for _, literal := range []string{"1/3", "Inf", "NaN", "1__0.5"} {
+			_, err := ParseBigRat(literal)
+			assert.ErrorIs(t, err, strconv.ErrSyntax, "literal: %q", literal)
+		}
+	})
+}
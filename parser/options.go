@@ -0,0 +1,34 @@
+package parser
+
+// Options configures parser, zero value is strict mode used for struct tags.
+type Options struct {
+	// Comments enables line comments (# ... and // ...) and block comments (/* ... */) which are skipped as whitespace.
+	// Spans of comments are available in Attribute.Comments of the top-level attribute.
+	Comments bool
+
+	// DedentStrings removes common indentation from multi-line strings ('''...''' and """...""") and line break
+	// right after opening quotes, so strings can be indented together with surrounding code.
+	DedentStrings bool
+
+	// TrailingCommas allows comma after last attribute of list or item of array (a=1, b[1, 2,],), such text is
+	// written by Print with TrailingComma option.
+	TrailingCommas bool
+
+	// RejectStrayCharacters reports characters that are not part of any token ('!', or '#' when Comments is disabled)
+	// as "unexpected character" errors. By default they are skipped, same as whitespace, for compatibility with
+	// existing attribute strings.
+	RejectStrayCharacters bool
+
+	// Syntax of input, nil means attribute grammar. Other syntaxes (StructTagSyntax, SemicolonSyntax) read legacy
+	// formats into the same AST, options above apply only to attribute grammar unless syntax documents otherwise.
+	Syntax Syntax
+}
+
+// Comment found in input (only when Options.Comments is enabled)
+type Comment struct {
+	// span in original string (including comment markers)
+	Span *SourceSpan
+
+	// Text of the comment including comment markers
+	Text string
+}
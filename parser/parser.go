@@ -1,13 +1,15 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"strings"
 )
 
 // MustParse panics on error, usually used in init funcs and/or tests
-func MustParse(input io.Reader) *Attribute {
-	result, err := Parse(input)
+func MustParse(input io.Reader, options ...Options) *Attribute {
+	result, err := Parse(input, options...)
 	if err != nil {
 		panic(err)
 	}
@@ -15,8 +17,45 @@ func MustParse(input io.Reader) *Attribute {
 }
 
 // Parse parses the input and returns the top-level attribute (Object holds all parsed attributes).
-func Parse(input io.Reader) (*Attribute, error) {
-	p := &parser{lexer: newLexer(input)}
+// Optional options change parser behavior, without them parser is strict.
+// Spans of returned attribute (and error) have Start and End locations resolved.
+func Parse(input io.Reader, options ...Options) (*Attribute, error) {
+	var (
+		result  *Attribute
+		content string
+		err     error
+	)
+	if len(options) > 0 && options[0].Syntax != nil {
+		all, readErr := io.ReadAll(input)
+		if readErr != nil {
+			return nil, readErr
+		}
+		content = string(all)
+		result, err = options[0].Syntax.Parse(content, options[0])
+	} else {
+		p := &parser{lexer: newLexer(input, options...)}
+		result, err = p.parse()
+		content = p.lexer.content
+	}
+
+	loc := newLocator(content)
+	if err != nil {
+		var ce ConflictError
+		if errors.As(err, &ce) {
+			loc.resolve(ce.ConflictSpan())
+		}
+		var pe ParseError
+		if errors.As(err, &pe) {
+			loc.resolve(pe.Span())
+		}
+		return nil, err
+	}
+	loc.resolveAttribute(result)
+	return result, nil
+}
+
+// parse parses whole input
+func (p *parser) parse() (*Attribute, error) {
 	span := newSourceSpan(0)
 
 	oa, err := p.parseAttributeList()
@@ -24,14 +63,18 @@ func Parse(input io.Reader) (*Attribute, error) {
 		return nil, err
 	}
 
-	_, tok, val := p.Lex()
+	tokSpan, tok, val := p.Lex()
+	if tok == TokenError {
+		return nil, tok.AsError(tokSpan, val)
+	}
 	if tok != TokenEOF {
 		return nil, NewParseError(p.currentSpan(), "unexpected token %s: %q", tok.String(), val)
 	}
 
 	return &Attribute{
-		Span:   span.withLengthFromPosition(p.currentPos()),
-		Object: oa,
+		Span:     span.withLengthFromPosition(p.currentPos()),
+		Object:   oa,
+		Comments: p.lexer.comments,
 	}, nil
 }
 
@@ -55,7 +98,10 @@ func (p *parser) parseAttributeList() (*Attributes, error) {
 
 		if !first {
 			commaSpan := p.currentSpan()
-			_, tok, val := p.Lex()
+			tokSpan, tok, val := p.Lex()
+			if tok == TokenError {
+				return nil, tok.AsError(tokSpan, val)
+			}
 			if tok != TokenComma {
 				return nil, NewParseError(commaSpan, "expected ',' but got %s %q", tok.String(), val)
 			}
@@ -65,6 +111,9 @@ func (p *parser) parseAttributeList() (*Attributes, error) {
 				return nil, NewParseError(p.currentSpan(), "unexpected double comma")
 			}
 			if nextTok == TokenEOF || nextTok == TokenCloseBracket {
+				if p.lexer.options.TrailingCommas {
+					break
+				}
 				return nil, NewParseError(commaSpan, "trailing comma not allowed")
 			}
 		}
@@ -82,65 +131,47 @@ func (p *parser) parseAttributeList() (*Attributes, error) {
 }
 
 // parseAttribute parses a single attribute which can be:
-//   - ident=value   (key=value)
+//   - ident=value   (key=value, ident can be dotted path db.pool.max)
 //   - ident(attrs)  (nested object)
 //   - ident[items]  (array)
 //   - ident         (bare boolean flag, equals ident=true)
+//   - string=value, string(attrs), string[items] (quoted key, e.g. 'Content-Type'=json)
 //   - string        (positional string value)
 //   - number        (positional number value)
 //   - (attrs)       (positional object)
 //   - [items]       (positional array)
 func (p *parser) parseAttribute() (*Attribute, error) {
 	span := p.currentSpan()
-	_, tok, val := p.Peek()
+	tokSpan, tok, val := p.Peek()
 
 	switch tok {
 	case TokenIdent:
 		p.Lex()
-		result := &Attribute{Name: val, Span: span}
-
-		_, nextTok, _ := p.Peek()
-		switch nextTok {
-		case TokenEqual:
-			p.Lex() // consume '='
-			v, err := p.parseValue()
-			if err != nil {
-				return nil, err
-			}
-			result.Value = &v
-
-		case TokenOpenBracket:
-			p.Lex() // consume '('
-			attrs, err := p.parseAttributeList()
-			if err != nil {
-				return nil, err
-			}
-			_, closeTok, _ := p.Lex()
-			if closeTok != TokenCloseBracket {
-				return nil, NewParseError(span, "expected ')' to close object %q", val)
-			}
-			result.Object = attrs
-
-		case TokenOpenSquareBracket:
-			p.Lex() // consume '['
-			arr, err := p.parseArray()
-			if err != nil {
-				return nil, err
-			}
-			_, closeTok, _ := p.Lex()
-			if closeTok != TokenCloseSquareBracket {
-				return nil, NewParseError(span, "expected ']' to close array %q", val)
-			}
-			result.Array = arr
-
-		default:
-			// bare boolean flag: ident with no following =, (, or [
-			trueStr := "true"
-			result.Value = &Value{Span: span, Boolean: &trueStr, String: &trueStr}
+		path, err := p.parsePath(val)
+		if err != nil {
+			return nil, err
+		}
+		// span of named attribute covers its name (or path)
+		nameSpan := tokSpan.withLengthFromPosition(p.currentPos())
+		if path == nil {
+			return p.parseNamedAttribute(nameSpan, val, false)
+		}
+		result, err := p.parseNamedAttribute(nameSpan, strings.Join(path, "."), false)
+		if err != nil {
+			return nil, err
 		}
+		result.Path = path
 		return result, nil
 
-	case TokenString, TokenNumber:
+	case TokenString:
+		p.Lex()
+		// string followed by '=', '(' or '[' is quoted key, otherwise it's positional value
+		if _, nextTok, _ := p.Peek(); nextTok == TokenEqual || nextTok == TokenOpenBracket || nextTok == TokenOpenSquareBracket {
+			return p.parseNamedAttribute(tokSpan.withLengthFromPosition(p.currentPos()), val, true)
+		}
+		return &Attribute{Span: span.withLengthFromPosition(p.currentPos()), Value: &Value{Span: tokSpan, String: &val}}, nil
+
+	case TokenNumber:
 		v, err := p.parseValue()
 		if err != nil {
 			return nil, err
@@ -171,11 +202,84 @@ func (p *parser) parseAttribute() (*Attribute, error) {
 		}
 		return &Attribute{Span: span.withLengthFromPosition(p.currentPos()), Array: arr}, nil
 
+	case TokenError:
+		return nil, tok.AsError(tokSpan, val)
+
 	default:
 		return nil, NewParseError(span, "unexpected token %s: %q", tok.String(), val)
 	}
 }
 
+// parsePath parses rest of dotted path (db.pool.max) after first identifier was consumed.
+// It returns nil for simple name.
+func (p *parser) parsePath(first string) ([]string, error) {
+	var path []string
+	for {
+		if _, tok, _ := p.Peek(); tok != TokenDot {
+			return path, nil
+		}
+		if path == nil {
+			path = []string{first}
+		}
+		p.Lex() // consume '.'
+		span, tok, val := p.Lex()
+		if tok == TokenError {
+			return nil, tok.AsError(span, val)
+		}
+		if tok != TokenIdent {
+			return nil, NewParseError(span, "expected identifier after '.' but got %s %q", tok.String(), val)
+		}
+		path = append(path, val)
+	}
+}
+
+// parseNamedAttribute parses rest of attribute after its name (identifier or quoted key) was consumed.
+func (p *parser) parseNamedAttribute(span *SourceSpan, name string, quoted bool) (*Attribute, error) {
+	result := &Attribute{Name: name, Span: span, Quoted: quoted}
+
+	_, nextTok, _ := p.Peek()
+	switch nextTok {
+	case TokenEqual:
+		p.Lex() // consume '='
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		result.Value = &v
+
+	case TokenOpenBracket:
+		p.Lex() // consume '('
+		attrs, err := p.parseAttributeList()
+		if err != nil {
+			return nil, err
+		}
+		_, closeTok, _ := p.Lex()
+		if closeTok != TokenCloseBracket {
+			return nil, NewParseError(span, "expected ')' to close object %q", name)
+		}
+		result.Object = attrs
+
+	case TokenOpenSquareBracket:
+		p.Lex() // consume '['
+		arr, err := p.parseArray()
+		if err != nil {
+			return nil, err
+		}
+		_, closeTok, _ := p.Lex()
+		if closeTok != TokenCloseSquareBracket {
+			return nil, NewParseError(span, "expected ']' to close array %q", name)
+		}
+		result.Array = arr
+
+	default:
+		// bare boolean flag: ident with no following =, (, or [
+		trueStr := "true"
+		result.Value = &Value{Span: span, Boolean: &trueStr, String: &trueStr}
+		result.Flag = true
+	}
+	return result, nil
+}
+
 // parseArray parses the body of an array (after '[' has already been consumed).
 // Returns when ']' is peeked (does not consume it).
 func (p *parser) parseArray() (*Attributes, error) {
@@ -205,10 +309,16 @@ func (p *parser) parseArray() (*Attributes, error) {
 			return nil, NewParseError(span, "unclosed array, expected ']'")
 		}
 
-		_, tok, val := p.Lex()
+		tokSpan, tok, val := p.Lex()
+		if tok == TokenError {
+			return nil, tok.AsError(tokSpan, val)
+		}
 		if tok != TokenComma {
 			return nil, NewParseError(span, "expected ',' in array but got %s %q", tok.String(), val)
 		}
+		if _, nextTok, _ := p.Peek(); nextTok == TokenCloseSquareBracket && p.lexer.options.TrailingCommas {
+			break
+		}
 
 		next, err := p.parseArrayItem()
 		if err != nil {
@@ -224,7 +334,7 @@ func (p *parser) parseArray() (*Attributes, error) {
 // parseArrayItem parses one element inside an array: a scalar value, a nested array, or an object.
 func (p *parser) parseArrayItem() (*Attribute, error) {
 	span := p.currentSpan()
-	_, tok, _ := p.Peek()
+	tokSpan, tok, val := p.Peek()
 
 	switch tok {
 	case TokenString, TokenNumber, TokenIdent:
@@ -258,12 +368,15 @@ func (p *parser) parseArrayItem() (*Attribute, error) {
 		}
 		return &Attribute{Span: span.withLengthFromPosition(p.currentPos()), Array: arr}, nil
 
+	case TokenError:
+		return nil, tok.AsError(tokSpan, val)
+
 	default:
 		return nil, NewParseError(span, "unexpected token %s in array", tok.String())
 	}
 }
 
-// parseValue parses a scalar value: string, number, or boolean/ident.
+// parseValue parses a scalar value: string, number, null, or boolean/ident.
 func (p *parser) parseValue() (Value, error) {
 	span, tok, val := p.Lex()
 	result := Value{Span: span}
@@ -272,6 +385,10 @@ func (p *parser) parseValue() (Value, error) {
 		result.String = &val
 		return result, nil
 	case TokenIdent:
+		if isNull(val) {
+			result.Null = true
+			return result, nil
+		}
 		result.String = &val
 		if val == "true" || val == "false" {
 			result.Boolean = &val
@@ -280,6 +397,8 @@ func (p *parser) parseValue() (Value, error) {
 	case TokenNumber:
 		result.Number = &val
 		return result, nil
+	case TokenError:
+		return result, tok.AsError(span, val)
 	default:
 		return result, NewParseError(span, "expected value, got %s: %q", tok.String(), val)
 	}
@@ -329,7 +448,7 @@ func (p *parser) LexSelected(tokens ...Token) (*SourceSpan, Token, string, error
 	return nextSpan, nextToken, nextValue, ErrNoMatch
 }
 
-// currentPos returns the current byte position in the input.
+// currentPos returns the current position (in runes) in the input.
 func (p *parser) currentPos() int {
 	if len(p.peekObjs) > 0 {
 		return p.peekObjs[len(p.peekObjs)-1].span.Position
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -70,6 +71,26 @@ func TestParse(t *testing.T) {
 		assert.Equal(t, ptr("-1.5"), a[0].Value.Number)
 	})
 
+	t.Run("key_equals_number_literals", func(t *testing.T) {
+		a := topAttrs(mustParse(t, "a=0xFF, b=1e6, c=+5, d=1_000, e=0b1010, f=-Inf"))
+		require.Len(t, a, 6)
+		for i, expected := range []string{"0xFF", "1e6", "+5", "1_000", "0b1010", "-Inf"} {
+			assert.Equal(t, ptr(expected), a[i].Value.Number)
+		}
+	})
+
+	t.Run("special_float_needs_sign", func(t *testing.T) {
+		a := topAttrs(mustParse(t, "a=Inf, b=NaN, c=+Inf, d=-NaN"))
+		require.Len(t, a, 4)
+		assert.Nil(t, a[0].Value.Number)
+		assert.Equal(t, ptr("Inf"), a[0].Value.String)
+		assert.Nil(t, a[1].Value.Number)
+		assert.Equal(t, ptr("NaN"), a[1].Value.String)
+		assert.Equal(t, ptr("+Inf"), a[2].Value.Number)
+		assert.Nil(t, a[2].Value.String)
+		assert.Equal(t, ptr("-NaN"), a[3].Value.Number)
+	})
+
 	t.Run("key_equals_single_quoted_string", func(t *testing.T) {
 		a := topAttrs(mustParse(t, "s='hello world'"))
 		require.Len(t, a, 1)
@@ -102,6 +123,24 @@ func TestParse(t *testing.T) {
 		assert.Nil(t, a[0].Value.Number)
 	})
 
+	t.Run("key_equals_null", func(t *testing.T) {
+		a := topAttrs(mustParse(t, "a=null, b=nil, c='null', items[1, null]"))
+		require.Len(t, a, 4)
+		for _, attr := range a[:2] {
+			require.NotNil(t, attr.Value)
+			assert.True(t, attr.Value.Null)
+			assert.False(t, attr.Value.IsZero())
+			assert.Nil(t, attr.Value.String)
+		}
+		assert.False(t, a[2].Value.Null)
+		assert.Equal(t, ptr("null"), a[2].Value.String)
+		assert.True(t, a[3].Array.Attributes[1].Value.Null)
+
+		built, err := a[0].Build()
+		require.NoError(t, err)
+		assert.Nil(t, built.Interface())
+	})
+
 	t.Run("key_equals_false", func(t *testing.T) {
 		a := topAttrs(mustParse(t, "ok=false"))
 		require.Len(t, a, 1)
@@ -375,6 +414,163 @@ func TestParse(t *testing.T) {
 		assert.Equal(t, ptr("it's fine"), a[0].Value.String)
 	})
 
+	t.Run("string_escape_sequences", func(t *testing.T) {
+		a := topAttrs(mustParse(t, `s="tab\t\"q\" \u00e9", r='\x41\\'`))
+		require.Len(t, a, 2)
+		assert.Equal(t, ptr("tab\t\"q\" é"), a[0].Value.String)
+		assert.Equal(t, ptr(`A\`), a[1].Value.String)
+	})
+
+	t.Run("raw_string", func(t *testing.T) {
+		a := topAttrs(mustParse(t, "pattern=`^\\d+$`, path=`C:\\tmp`, items[`a\\b`]"))
+		require.Len(t, a, 3)
+		assert.Equal(t, ptr(`^\d+$`), a[0].Value.String)
+		assert.Equal(t, ptr(`C:\tmp`), a[1].Value.String)
+		require.Len(t, a[2].Array.Attributes, 1)
+		assert.Equal(t, ptr(`a\b`), a[2].Array.Attributes[0].Value.String)
+	})
+
+	t.Run("error_unknown_escape_has_exact_span", func(t *testing.T) {
+		for _, input := range []string{`s="ab\qc"`, `'ab\qc'`, `items['ab\qc']`, `s='x', t="ab\qc"`} {
+			_, err := Parse(strings.NewReader(input))
+			require.Error(t, err, "input: %q", input)
+			assert.ErrorContains(t, err, `unknown escape sequence \q`, "input: %q", input)
+			var pe ParseError
+			require.True(t, errors.As(err, &pe), "input: %q", input)
+			assert.Equal(t, strings.Index(input, `\q`), pe.Position(), "input: %q", input)
+		}
+	})
+
+	t.Run("comments", func(t *testing.T) {
+		input := "# leading\nid=1, // trailing\nname='x' /* block */, inner(a=1 # nested\n)"
+		got, err := Parse(strings.NewReader(input), Options{Comments: true})
+		require.NoError(t, err)
+		a := topAttrs(got)
+		require.Len(t, a, 3)
+		assert.Equal(t, "id", a[0].Name)
+		assert.Equal(t, ptr("x"), a[1].Value.String)
+		require.Len(t, a[2].Object.Attributes, 1)
+
+		require.Len(t, got.Comments, 4)
+		for i, expected := range []string{"# leading", "// trailing", "/* block */", "# nested"} {
+			assert.Equal(t, expected, got.Comments[i].Text)
+			assert.Equal(t, strings.Index(input, expected), got.Comments[i].Span.Position)
+			assert.Equal(t, len(expected), got.Comments[i].Span.Length)
+		}
+	})
+
+	t.Run("comments_rejected_as_stray_characters", func(t *testing.T) {
+		for _, input := range []string{"id=1 # comment", "id=1 // comment", "id=1, /* comment */ name=2", "id=1 #"} {
+			_, err := Parse(strings.NewReader(input), Options{RejectStrayCharacters: true})
+			require.Error(t, err, "input: %q", input)
+			assert.ErrorContains(t, err, "unexpected character", "input: %q", input)
+		}
+		assert.Empty(t, mustParse(t, "id=1").Comments)
+	})
+
+	t.Run("error_unterminated_block_comment", func(t *testing.T) {
+		input := "id=1, /* name=2"
+		_, err := Parse(strings.NewReader(input), Options{Comments: true})
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unterminated block comment")
+		var pe ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, strings.Index(input, "/*"), pe.Position())
+	})
+
+	t.Run("quoted_keys", func(t *testing.T) {
+		a := topAttrs(mustParse(t, `headers('Content-Type'='json', "x.y"=1, '1'(a=1), 'list'[1]), id=1, 'positional'`))
+		require.Len(t, a, 3)
+		assert.False(t, a[0].Quoted)
+		require.NotNil(t, a[0].Object)
+		h := a[0].Object.Attributes
+		require.Len(t, h, 4)
+		for i, name := range []string{"Content-Type", "x.y", "1", "list"} {
+			assert.Equal(t, name, h[i].Name)
+			assert.True(t, h[i].Quoted)
+		}
+		assert.Equal(t, ptr("json"), h[0].Value.String)
+		assert.Equal(t, ptr("1"), h[1].Value.Number)
+		require.NotNil(t, h[2].Object)
+		require.NotNil(t, h[3].Array)
+		assert.False(t, a[1].Quoted)
+		assert.Equal(t, "", a[2].Name)
+		assert.False(t, a[2].Quoted)
+		assert.Equal(t, ptr("positional"), a[2].Value.String)
+	})
+
+	t.Run("dotted_path", func(t *testing.T) {
+		a := topAttrs(mustParse(t, "db.pool.max=10, db.name='x', db.pool(min=1), db.tags[1], db.enabled, plain=1"))
+		require.Len(t, a, 6)
+		assert.Equal(t, "db.pool.max", a[0].Name)
+		assert.Equal(t, []string{"db", "pool", "max"}, a[0].Path)
+		assert.Equal(t, ptr("10"), a[0].Value.Number)
+		assert.Equal(t, []string{"db", "name"}, a[1].Path)
+		assert.NotNil(t, a[2].Object)
+		assert.NotNil(t, a[3].Array)
+		assert.Equal(t, ptr("true"), a[4].Value.Boolean)
+		assert.Nil(t, a[5].Path)
+	})
+
+	t.Run("error_dotted_path", func(t *testing.T) {
+		for _, input := range []string{"db.=1", "db.'x'=1", "db..x=1", "db.1=1"} {
+			_, err := Parse(strings.NewReader(input))
+			assert.Error(t, err, "input: %q", input)
+		}
+	})
+
+	t.Run("multi_line_string", func(t *testing.T) {
+		input := "sql='''\n  SELECT *\n  FROM t\n''', next=1"
+		a := topAttrs(mustParse(t, input))
+		require.Len(t, a, 2)
+		assert.Equal(t, ptr("\n  SELECT *\n  FROM t\n"), a[0].Value.String)
+		span := a[0].Value.Span
+		assert.Equal(t, strings.Index(input, "'''"), span.Position)
+		assert.Equal(t, len("'''\n  SELECT *\n  FROM t\n'''"), span.Length)
+		assert.Equal(t, Location{Offset: 4, Rune: 4, Line: 1, Column: 5}, span.Start)
+		assert.Equal(t, Location{Offset: 31, Rune: 31, Line: 4, Column: 4}, span.End)
+		assert.Equal(t, strings.Index(input, "1"), a[1].Value.Span.Position)
+
+		got, err := Parse(strings.NewReader(input), Options{DedentStrings: true})
+		require.NoError(t, err)
+		assert.Equal(t, ptr("SELECT *\nFROM t\n"), topAttrs(got)[0].Value.String)
+	})
+
+	t.Run("error_in_multi_line_string_has_exact_span", func(t *testing.T) {
+		input := "a='ž', sql=\"\"\"\n  čšť\n  \\q\n\"\"\""
+		_, err := Parse(strings.NewReader(input))
+		require.Error(t, err)
+		assert.ErrorContains(t, err, `unknown escape sequence \q`)
+		var pe ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, utf8.RuneCountInString(input[:strings.Index(input, `\q`)]), pe.Position())
+	})
+
+	t.Run("spans_have_line_and_column", func(t *testing.T) {
+		input := "id=1,\n  name='čšť',\n  tags[\n    x]"
+		a := topAttrs(mustParse(t, input))
+		require.Len(t, a, 3)
+		assert.Equal(t, Location{Offset: 13, Rune: 13, Line: 2, Column: 8}, a[1].Value.Span.Start)
+		assert.Equal(t, Location{Offset: 21, Rune: 18, Line: 2, Column: 13}, a[1].Value.Span.End)
+		item := a[2].Array.Attributes[0].Value.Span
+		assert.Equal(t, 4, item.Start.Line)
+		assert.Equal(t, 5, item.Start.Column)
+		assert.Equal(t, len(input)-2, item.Start.Offset)
+	})
+
+	t.Run("error_has_line_and_column", func(t *testing.T) {
+		input := "a='ž',\nb='čš\\q'"
+		_, err := Parse(strings.NewReader(input))
+		require.Error(t, err)
+		var pe ParseError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, 12, pe.Position())
+		assert.Equal(t, 2, pe.Line())
+		assert.Equal(t, 6, pe.Column())
+		assert.Equal(t, strings.Index(input, `\q`), pe.Span().Start.Offset)
+		assert.Equal(t, `[span: Span[Position: 12, Length: 2]] unknown escape sequence \q`, err.Error())
+	})
+
 	t.Run("key_with_underscore", func(t *testing.T) {
 		a := topAttrs(mustParse(t, "my_key=1"))
 		require.Len(t, a, 1)
@@ -1236,6 +1432,44 @@ func TestSourceSpan(t *testing.T) {
 		assert.Contains(t, str, "6")
 	})
 
+	t.Run("String_with_location", func(t *testing.T) {
+		s := newSourceSpan(4, 6)
+		newLocator("ab\ncdefghijk").resolve(s)
+		assert.Equal(t, "Span[Position: 4, Length: 6]", s.String())
+	})
+
+	t.Run("locator_resolves_ascii", func(t *testing.T) {
+		loc := newLocator("ab\ncd\n")
+		for _, item := range []struct {
+			position int
+			expected Location
+		}{
+			{position: 0, expected: Location{Offset: 0, Rune: 0, Line: 1, Column: 1}},
+			{position: 2, expected: Location{Offset: 2, Rune: 2, Line: 1, Column: 3}},
+			{position: 3, expected: Location{Offset: 3, Rune: 3, Line: 2, Column: 1}},
+			{position: 6, expected: Location{Offset: 6, Rune: 6, Line: 3, Column: 1}},
+			{position: 100, expected: Location{Offset: 6, Rune: 6, Line: 3, Column: 1}},
+		} {
+			assert.Equal(t, item.expected, loc.location(item.position), "position: %d", item.position)
+		}
+	})
+
+	t.Run("locator_resolves_non_ascii", func(t *testing.T) {
+		loc := newLocator("ž=1,\nčš='ť'")
+		for _, item := range []struct {
+			position int
+			expected Location
+		}{
+			{position: 1, expected: Location{Offset: 2, Rune: 1, Line: 1, Column: 2}},
+			{position: 5, expected: Location{Offset: 6, Rune: 5, Line: 2, Column: 1}},
+			{position: 7, expected: Location{Offset: 10, Rune: 7, Line: 2, Column: 3}},
+			{position: 9, expected: Location{Offset: 12, Rune: 9, Line: 2, Column: 5}},
+			{position: 11, expected: Location{Offset: 15, Rune: 11, Line: 2, Column: 7}},
+		} {
+			assert.Equal(t, item.expected, loc.location(item.position), "position: %d", item.position)
+		}
+	})
+
 	t.Run("immutability_original_unchanged", func(t *testing.T) {
 		original := newSourceSpan(1, 2)
 		_ = original.withLength(99)
@@ -1461,3 +1695,64 @@ func TestParseAndBuild(t *testing.T) {
 		assert.Equal(t, int(10), sl[0])
 	})
 }
+
+func TestAttributesExpandPaths(t *testing.T) {
+	names := func(attrs *Attributes) []string {
+		var result []string
+		for _, attr := range attrs.Attributes {
+			result = append(result, attr.Name)
+		}
+		return result
+	}
+
+	t.Run("without_paths_returns_same", func(t *testing.T) {
+		attrs := mustParse(t, "a=1, b(c=2), 'x'").Object
+		expanded, err := attrs.ExpandPaths()
+		require.NoError(t, err)
+		assert.Same(t, attrs, expanded)
+	})
+
+	t.Run("merges_paths", func(t *testing.T) {
+		expanded, err := mustParse(t, "db.pool.max=10, id=1, db.pool.min=1, db.name='x'").Object.ExpandPaths()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"db", "id"}, names(expanded))
+
+		db := expanded.Attributes[0]
+		require.NotNil(t, db.Object)
+		assert.Nil(t, db.Path)
+		assert.Equal(t, []string{"pool.max", "pool.min", "name"}, names(db.Object))
+		assert.Equal(t, []string{"pool", "max"}, db.Object.Attributes[0].Path)
+		assert.Nil(t, db.Object.Attributes[2].Path)
+
+		pool, err := db.Object.ExpandPaths()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"pool", "name"}, names(pool))
+		assert.Equal(t, []string{"max", "min"}, names(pool.Attributes[0].Object))
+		assert.Equal(t, ptr("10"), pool.Attributes[0].Object.Attributes[0].Value.Number)
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+			position int
+			length   int
+			conflict int
+			otherLen int
+		}{
+			{input: "db(name='x'), db.pool.max=10", expected: "path db.pool.max conflicts with attribute db", position: 14, length: 11, conflict: 0, otherLen: 2},
+			{input: "db.pool.max=10, db=1", expected: "attribute db conflicts with path db.pool.max", position: 16, length: 2, conflict: 0, otherLen: 11},
+			{input: "a=1, db.x=1, 'db'=2", expected: "attribute db conflicts with path db.x", position: 13, length: 4, conflict: 5, otherLen: 4},
+		} {
+			_, err := mustParse(t, item.input).Object.ExpandPaths()
+			require.Error(t, err, "input: %q", item.input)
+			assert.ErrorContains(t, err, item.expected, "input: %q", item.input)
+			var ce ConflictError
+			require.True(t, errors.As(err, &ce), "input: %q", item.input)
+			assert.Equal(t, item.position, ce.Position(), "input: %q", item.input)
+			assert.Equal(t, item.length, ce.Span().Length, "input: %q", item.input)
+			assert.Equal(t, item.conflict, ce.ConflictPosition(), "input: %q", item.input)
+			assert.Equal(t, item.otherLen, ce.ConflictSpan().Length, "input: %q", item.input)
+		}
+	})
+}
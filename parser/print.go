@@ -0,0 +1,251 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Layout of text written by Print
+type Layout int
+
+const (
+	// LayoutLine writes everything on single line, attributes are separated by comma and space (a=1, b(c=2))
+	LayoutLine Layout = iota
+	// LayoutCompact writes everything on single line without spaces (a=1,b(c=2))
+	LayoutCompact
+	// LayoutMultiline writes every attribute of object on its own line and indents nested objects, arrays of
+	// scalar values are kept on single line
+	LayoutMultiline
+)
+
+// QuoteStyle selects quotes of strings written by Print
+type QuoteStyle int
+
+const (
+	// QuoteSingle quotes strings with single quotes ('value')
+	QuoteSingle QuoteStyle = iota
+	// QuoteDouble quotes strings with double quotes ("value")
+	QuoteDouble
+	// QuoteAuto picks quotes that need fewer escapes, single quotes on tie
+	QuoteAuto
+)
+
+// TrailingComma selects when Print writes comma after last attribute or item. Text with trailing commas is parsed
+// only with Options.TrailingCommas.
+type TrailingComma int
+
+const (
+	// TrailingCommaNever never writes trailing comma
+	TrailingCommaNever TrailingComma = iota
+	// TrailingCommaMultiline writes trailing comma only to lists split into lines (LayoutMultiline)
+	TrailingCommaMultiline
+	// TrailingCommaAlways writes trailing comma to every non-empty list
+	TrailingCommaAlways
+)
+
+// PrintOptions configures Print, zero value writes canonical single line text.
+type PrintOptions struct {
+	Layout Layout
+
+	// Indent of nested lines with LayoutMultiline, default is tab
+	Indent string
+
+	Quote QuoteStyle
+
+	TrailingComma TrailingComma
+
+	// SortKeys sorts named attributes of every object by name, positional values are written first in original
+	// order (their order is meaningful)
+	SortKeys bool
+}
+
+// Print writes attribute back to text. Top-level attribute returned by Parse (object without name) is written
+// as list of its attributes. Names and string values are quoted only when needed, numbers keep their literal
+// and bare flags are written without value, so Parse(Print(x)) is structurally equal to x (spans and comments
+// are not kept).
+func Print(a *Attribute, options PrintOptions) string {
+	p := &printer{options: options}
+	if p.options.Indent == "" {
+		p.options.Indent = "\t"
+	}
+
+	if a.Name == "" && !a.Quoted && a.Object != nil {
+		p.printList(a.Object.Attributes, 0, true)
+	} else {
+		p.printAttribute(a, 0)
+	}
+	return p.sb.String()
+}
+
+// printer holds state of Print
+type printer struct {
+	options PrintOptions
+	sb      strings.Builder
+}
+
+// printList writes attributes separated by commas, top-level list has no brackets, so its lines are not indented
+func (p *printer) printList(attrs []*Attribute, depth int, object bool) {
+	if len(attrs) == 0 {
+		return
+	}
+	if object && p.options.SortKeys {
+		attrs = sortAttributes(attrs)
+	}
+
+	multiline := p.options.Layout == LayoutMultiline && (object || !scalarItems(attrs))
+	for i, attr := range attrs {
+		if i > 0 {
+			p.sb.WriteByte(',')
+			switch {
+			case multiline:
+				p.newline(depth)
+			case p.options.Layout != LayoutCompact:
+				p.sb.WriteByte(' ')
+			}
+		} else if multiline && depth > 0 {
+			p.newline(depth)
+		}
+		p.printAttribute(attr, depth)
+	}
+
+	if p.options.TrailingComma == TrailingCommaAlways || p.options.TrailingComma == TrailingCommaMultiline && multiline {
+		p.sb.WriteByte(',')
+	}
+	if multiline && depth > 0 {
+		p.newline(depth - 1)
+	}
+}
+
+func (p *printer) printAttribute(a *Attribute, depth int) {
+	named := a.Name != "" || a.Quoted
+	if named {
+		if a.Quoted || a.Path == nil && !isIdentifier(a.Name) {
+			p.sb.WriteString(p.quote(a.Name))
+		} else {
+			p.sb.WriteString(a.Name)
+		}
+	}
+
+	switch {
+	case a.Object != nil:
+		p.sb.WriteByte('(')
+		p.printList(a.Object.Attributes, depth+1, true)
+		p.sb.WriteByte(')')
+	case a.Array != nil:
+		p.sb.WriteByte('[')
+		p.printList(a.Array.Attributes, depth+1, false)
+		p.sb.WriteByte(']')
+	case a.Flag && named:
+		// bare flag has no value in text
+	case a.Value != nil:
+		if named {
+			p.sb.WriteByte('=')
+		}
+		p.printValue(a.Value, named)
+	}
+}
+
+// printValue writes scalar value, positional strings are always quoted (bare identifier would be flag)
+func (p *printer) printValue(v *Value, named bool) {
+	switch {
+	case v.Null:
+		p.sb.WriteString("null")
+	case v.Boolean != nil:
+		p.sb.WriteString(*v.Boolean)
+	case v.Number != nil:
+		p.sb.WriteString(*v.Number)
+	case v.String != nil && named && isBareString(*v.String):
+		p.sb.WriteString(*v.String)
+	case v.String != nil:
+		p.sb.WriteString(p.quote(*v.String))
+	default:
+		p.sb.WriteString(p.quote(""))
+	}
+}
+
+func (p *printer) newline(depth int) {
+	p.sb.WriteByte('\n')
+	p.sb.WriteString(strings.Repeat(p.options.Indent, depth))
+}
+
+// quote quotes string with selected quotes, quote, backslash, control characters and invalid UTF-8 are escaped
+func (p *printer) quote(s string) string {
+	quote := byte('\'')
+	switch p.options.Quote {
+	case QuoteDouble:
+		quote = '"'
+	case QuoteAuto:
+		if strings.Count(s, "'") > strings.Count(s, `"`) {
+			quote = '"'
+		}
+	}
+
+	var sb strings.Builder
+	sb.WriteByte(quote)
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, `\x%02x`, s[i])
+		case r == rune(quote), r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r == '\n':
+			sb.WriteString(`\n`)
+		case r == '\r':
+			sb.WriteString(`\r`)
+		case r == '\t':
+			sb.WriteString(`\t`)
+		case r < 0x20 || r == 0x7f:
+			fmt.Fprintf(&sb, `\x%02x`, r)
+		default:
+			sb.WriteRune(r)
+		}
+		i += size
+	}
+	sb.WriteByte(quote)
+	return sb.String()
+}
+
+// sortAttributes returns positional attributes in original order followed by named attributes sorted by name
+func sortAttributes(attrs []*Attribute) []*Attribute {
+	result := make([]*Attribute, len(attrs))
+	copy(result, attrs)
+	sort.SliceStable(result, func(i, j int) bool {
+		iNamed := result[i].Name != "" || result[i].Quoted
+		jNamed := result[j].Name != "" || result[j].Quoted
+		if iNamed != jNamed {
+			return jNamed
+		}
+		return iNamed && result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// scalarItems returns whether all items are scalar values (array of them is kept on single line)
+func scalarItems(attrs []*Attribute) bool {
+	for _, attr := range attrs {
+		if attr.Value == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// isIdentifier returns whether s is lexed as single identifier
+func isIdentifier(s string) bool {
+	for i, r := range s {
+		if !unicode.IsLetter(r) && r != '_' && (i == 0 || !unicode.IsNumber(r)) {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// isBareString returns whether string value can be written without quotes, so it's not read back as other value
+func isBareString(s string) bool {
+	return isIdentifier(s) && !isNull(s) && s != "true" && s != "false"
+}
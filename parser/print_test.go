@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// structure returns attribute without spans and comments, so attributes can be compared structurally
+func structure(a *Attribute) map[string]any {
+	result := map[string]any{"name": a.Name, "quoted": a.Quoted, "path": a.Path, "flag": a.Flag}
+	if a.Value != nil {
+		result["value"] = []any{a.Value.String, a.Value.Number, a.Value.Boolean, a.Value.Null}
+	}
+	list := func(attrs *Attributes) []any {
+		items := []any{}
+		for _, attr := range attrs.Attributes {
+			items = append(items, structure(attr))
+		}
+		return items
+	}
+	if a.Object != nil {
+		result["object"] = list(a.Object)
+	}
+	if a.Array != nil {
+		result["array"] = list(a.Array)
+	}
+	return result
+}
+
+// checkRoundTrip fails when printed attribute is not parsed back to the same structure
+func checkRoundTrip(t *testing.T, a *Attribute, options PrintOptions) {
+	t.Helper()
+	printed := Print(a, options)
+	reparsed, err := Parse(strings.NewReader(printed), Options{TrailingCommas: true})
+	if err != nil {
+		t.Fatalf("Parse(Print(x)) with %+v failed: %v\nprinted: %q", options, err, printed)
+	}
+	if !assert.Equal(t, structure(a), structure(reparsed), "printed: %q", printed) {
+		t.FailNow()
+	}
+}
+
+func TestPrint(t *testing.T) {
+	t.Run("test canonical", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+		}{
+			{input: "", expected: ""},
+			{input: "  name  =  x,flag", expected: "name=x, flag"},
+			{input: "required=true", expected: "required=true"},
+			{input: `name="hello world"`, expected: `name='hello world'`},
+			{input: `s='it\'s', b="back\\slash", nl='a\nb', c='\x01\x7f'`, expected: `s='it\'s', b='back\\slash', nl='a\nb', c='\x01\x7f'`},
+			{input: `k='true', n='null', i='Inf', e='', d='a-b', u='ž'`, expected: `k='true', n='null', i=Inf, e='', d='a-b', u=ž`},
+			{input: "t=true, f=false, n=nil, i=Inf", expected: "t=true, f=false, n=null, i=Inf"},
+			{input: "n=0x1F, f=1_000.5, m=-1, p=+0.5", expected: "n=0x1F, f=1_000.5, m=-1, p=+0.5"},
+			{input: "'Content-Type'=json, 'x'(a=1), ''[1], 'flag'=true", expected: "'Content-Type'=json, 'x'(a=1), ''[1], 'flag'=true"},
+			{input: "'pos', 42, (a=1), [1]", expected: "'pos', 42, (a=1), [1]"},
+			{input: "arr[ 1,'two', ( a=b ), true, [x], null ], obj( ), empty[]", expected: "arr[1, 'two', (a=b), true, ['x'], null], obj(), empty[]"},
+			{input: "db.pool.max=10", expected: "db.pool.max=10"},
+			{input: "s='''\nmulti\nline'''", expected: `s='\nmulti\nline'`},
+			{input: `s='\xff'`, expected: `s='\xff'`},
+			{input: "a=1,b[1,],", expected: "a=1, b[1]"},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				parsed, err := Parse(strings.NewReader(item.input), Options{TrailingCommas: true})
+				require.NoError(t, err)
+				assert.Equal(t, item.expected, Print(parsed, PrintOptions{}))
+				checkRoundTrip(t, parsed, PrintOptions{})
+			})
+		}
+	})
+
+	t.Run("test options", func(t *testing.T) {
+		const input = "name='it\\'s', z=1, 'pos', a(c=1, b[1, 2], d[(x=1), [2]], e()), flag"
+		for _, item := range []struct {
+			name     string
+			options  PrintOptions
+			expected string
+		}{
+			{
+				name:     "compact",
+				options:  PrintOptions{Layout: LayoutCompact},
+				expected: `name='it\'s',z=1,'pos',a(c=1,b[1,2],d[(x=1),[2]],e()),flag`,
+			},
+			{
+				name:     "double quotes",
+				options:  PrintOptions{Quote: QuoteDouble},
+				expected: `name="it's", z=1, "pos", a(c=1, b[1, 2], d[(x=1), [2]], e()), flag`,
+			},
+			{
+				name:     "auto quotes",
+				options:  PrintOptions{Quote: QuoteAuto},
+				expected: `name="it's", z=1, 'pos', a(c=1, b[1, 2], d[(x=1), [2]], e()), flag`,
+			},
+			{
+				name:     "sorted keys",
+				options:  PrintOptions{SortKeys: true},
+				expected: `'pos', a(b[1, 2], c=1, d[(x=1), [2]], e()), flag, name='it\'s', z=1`,
+			},
+			{
+				name:     "trailing comma always",
+				options:  PrintOptions{TrailingComma: TrailingCommaAlways},
+				expected: `name='it\'s', z=1, 'pos', a(c=1, b[1, 2,], d[(x=1,), [2,],], e(),), flag,`,
+			},
+			{
+				name:     "trailing comma multiline in line layout",
+				options:  PrintOptions{TrailingComma: TrailingCommaMultiline},
+				expected: `name='it\'s', z=1, 'pos', a(c=1, b[1, 2], d[(x=1), [2]], e()), flag`,
+			},
+			{
+				name:     "multiline",
+				options:  PrintOptions{Layout: LayoutMultiline},
+				expected: "name='it\\'s',\nz=1,\n'pos',\na(\n\tc=1,\n\tb[1, 2],\n\td[\n\t\t(\n\t\t\tx=1\n\t\t),\n\t\t[2]\n\t],\n\te()\n),\nflag",
+			},
+			{
+				name:     "multiline with indent and trailing comma",
+				options:  PrintOptions{Layout: LayoutMultiline, Indent: "  ", TrailingComma: TrailingCommaMultiline},
+				expected: "name='it\\'s',\nz=1,\n'pos',\na(\n  c=1,\n  b[1, 2],\n  d[\n    (\n      x=1,\n    ),\n    [2],\n  ],\n  e(),\n),\nflag,",
+			},
+		} {
+			t.Run(item.name, func(t *testing.T) {
+				parsed := MustParse(strings.NewReader(input))
+				printed := Print(parsed, item.options)
+				assert.Equal(t, item.expected, printed)
+				if item.options.SortKeys {
+					// sorting changes order, but printing sorted text again doesn't
+					assert.Equal(t, printed, Print(MustParse(strings.NewReader(printed)), item.options))
+					return
+				}
+				checkRoundTrip(t, parsed, item.options)
+			})
+		}
+	})
+
+	t.Run("test single attribute", func(t *testing.T) {
+		parsed := MustParse(strings.NewReader("a(b=1), c"))
+		assert.Equal(t, "a(b=1)", Print(parsed.Object.Attributes[0], PrintOptions{}))
+		assert.Equal(t, "c", Print(parsed.Object.Attributes[1], PrintOptions{}))
+	})
+
+	t.Run("test hand built attribute", func(t *testing.T) {
+		value := "x"
+		attr := &Attribute{Object: newAttributes(nil,
+			&Attribute{Name: "not-identifier", Value: &Value{String: &value}},
+			&Attribute{Name: "empty", Value: &Value{}},
+		)}
+		assert.Equal(t, "'not-identifier'=x, empty=''", Print(attr, PrintOptions{}))
+	})
+}
+
+func TestParseTrailingCommas(t *testing.T) {
+	for _, input := range []string{"a=1,", "a(b=1,)", "a[1,]", "a[(b=1,),]", "'x',"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(input))
+			assert.Error(t, err)
+			_, err = Parse(strings.NewReader(input), Options{TrailingCommas: true})
+			assert.NoError(t, err)
+		})
+	}
+
+	for _, input := range []string{",", "a=1,,", "a[,]", "a(,)", "a[1,,]"} {
+		t.Run(input, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(input), Options{TrailingCommas: true})
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrInvalidQuery = errors.New("invalid query")
+)
+
+// Query is compiled path query, see CompileQuery for syntax
+type Query struct {
+	query    string
+	segments []querySegment
+}
+
+// querySegment is single step of query: name of attribute or index (of array item or positional value)
+type querySegment struct {
+	name  string
+	index int
+	// wildcard matches all names or indexes
+	wildcard bool
+	isIndex  bool
+}
+
+// CompileQuery compiles path query. Query is sequence of segments:
+//   - name or .name selects attribute of object by name (span.start), name is identifier or quoted string
+//     ('Content-Type') and * selects all named attributes
+//   - [n] selects array item or positional value of object by index, negative index counts from end and
+//     [*] selects all of them (users[*].username)
+//
+// Empty query selects root itself.
+func CompileQuery(query string) (*Query, error) {
+	result := &Query{query: query}
+	for i := 0; i < len(query); {
+		start := i
+		switch {
+		case query[i] == '[':
+			end := strings.IndexByte(query[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w %q: unclosed '[' at %d", ErrInvalidQuery, query, i)
+			}
+			index := query[i+1 : i+end]
+			segment := querySegment{isIndex: true, wildcard: index == "*"}
+			if !segment.wildcard {
+				value, err := strconv.Atoi(index)
+				if err != nil {
+					return nil, fmt.Errorf("%w %q: invalid index %q at %d", ErrInvalidQuery, query, index, i)
+				}
+				segment.index = value
+			}
+			result.segments = append(result.segments, segment)
+			i += end + 1
+			continue
+		case query[i] == '.':
+			if i == 0 {
+				return nil, fmt.Errorf("%w %q: unexpected '.' at 0", ErrInvalidQuery, query)
+			}
+			i++
+		case i > 0:
+			return nil, fmt.Errorf("%w %q: expected '.' or '[' at %d", ErrInvalidQuery, query, i)
+		}
+
+		name, size, err := queryName(query[i:])
+		if err != nil {
+			return nil, fmt.Errorf("%w %q: %v at %d", ErrInvalidQuery, query, err, start)
+		}
+		result.segments = append(result.segments, querySegment{name: name, wildcard: name == "*" && query[i] == '*'})
+		i += size
+	}
+	return result, nil
+}
+
+// queryName reads name at the beginning of query and returns it with its size in bytes
+func queryName(query string) (string, int, error) {
+	if query == "" {
+		return "", 0, errors.New("expected name")
+	}
+	switch query[0] {
+	case '*':
+		return "*", 1, nil
+	case '\'', '"':
+		// find closing quote, escaped quotes are skipped
+		for i := 1; i < len(query); i++ {
+			switch query[i] {
+			case '\\':
+				i++
+			case query[0]:
+				l := newLexer(strings.NewReader(query[:i+1]))
+				span, tok, value := l.Lex()
+				if tok != TokenString {
+					return "", 0, tok.AsError(span, value)
+				}
+				return value, i + 1, nil
+			}
+		}
+		return "", 0, errors.New("unterminated string")
+	}
+
+	size := strings.IndexAny(query, ".[")
+	if size < 0 {
+		size = len(query)
+	}
+	if !isIdentifier(query[:size]) {
+		return "", 0, fmt.Errorf("invalid name %q", query[:size])
+	}
+	return query[:size], size, nil
+}
+
+// String returns query as it was compiled
+func (q *Query) String() string {
+	return q.query
+}
+
+// Find returns attributes matching query in order they were written. Dotted paths (db.pool.max=10) are
+// expanded, so they are found as nested attributes (db.pool.max) and returned attribute has Name of the last
+// segment. Error is returned when path conflicts with attribute (see Attributes.ExpandPaths).
+func (q *Query) Find(root *Attribute) ([]*Attribute, error) {
+	current := []*Attribute{root}
+	for _, segment := range q.segments {
+		var next []*Attribute
+		for _, attr := range current {
+			matched, err := segment.match(attr)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, matched...)
+		}
+		current = next
+	}
+	return current, nil
+}
+
+// match returns children of attribute matching segment
+func (s querySegment) match(attr *Attribute) ([]*Attribute, error) {
+	var candidates []*Attribute
+	switch {
+	case s.isIndex && attr.Array != nil:
+		candidates = attr.Array.Attributes
+	case attr.Object != nil:
+		expanded, err := attr.Object.ExpandPaths()
+		if err != nil {
+			return nil, err
+		}
+		for _, child := range expanded.Attributes {
+			if positional := child.Name == "" && !child.Quoted; positional == s.isIndex {
+				candidates = append(candidates, child)
+			}
+		}
+	}
+
+	if s.wildcard {
+		return candidates, nil
+	}
+	if s.isIndex {
+		index := s.index
+		if index < 0 {
+			index += len(candidates)
+		}
+		if index < 0 || index >= len(candidates) {
+			return nil, nil
+		}
+		return candidates[index : index+1], nil
+	}
+
+	var result []*Attribute
+	for _, child := range candidates {
+		if child.Name == s.name {
+			result = append(result, child)
+		}
+	}
+	return result, nil
+}
+
+// Find returns attributes of root matching query (see CompileQuery for syntax), for example span.start
+// or users[*].username.
+func Find(root *Attribute, query string) ([]*Attribute, error) {
+	q, err := CompileQuery(query)
+	if err != nil {
+		return nil, err
+	}
+	return q.Find(root)
+}
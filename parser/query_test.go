@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFind(t *testing.T) {
+	const input = "'first', 42, name=x, span(start=0, end=255), users[(username='alice', admin), (username='bob')], " +
+		"'Content-Type'=json, db.pool.max=10, db.pool.min=1, rows[[1, 2], [3]]"
+	root := MustParse(strings.NewReader(input))
+
+	// values returns string form of found values
+	values := func(attrs []*Attribute) []string {
+		result := []string{}
+		for _, attr := range attrs {
+			switch {
+			case attr.Value == nil:
+				result = append(result, attr.Name+"()")
+			case attr.Value.Number != nil:
+				result = append(result, *attr.Value.Number)
+			default:
+				result = append(result, *attr.Value.String)
+			}
+		}
+		return result
+	}
+
+	for _, item := range []struct {
+		query    string
+		expected []string
+	}{
+		{query: "name", expected: []string{"x"}},
+		{query: "span.start", expected: []string{"0"}},
+		{query: "span.*", expected: []string{"0", "255"}},
+		{query: "span", expected: []string{"span()"}},
+		{query: "users[*].username", expected: []string{"alice", "bob"}},
+		{query: "users[1].username", expected: []string{"bob"}},
+		{query: "users[-1].username", expected: []string{"bob"}},
+		{query: "users[0].admin", expected: []string{"true"}},
+		{query: "users[2].username", expected: []string{}},
+		{query: "'Content-Type'", expected: []string{"json"}},
+		{query: `"Content-Type"`, expected: []string{"json"}},
+		{query: "db.pool.max", expected: []string{"10"}},
+		{query: "db.pool.*", expected: []string{"10", "1"}},
+		{query: "[0]", expected: []string{"first"}},
+		{query: "[*]", expected: []string{"first", "42"}},
+		{query: "rows[0][1]", expected: []string{"2"}},
+		{query: "rows[*][*]", expected: []string{"1", "2", "3"}},
+		{query: "name.x", expected: []string{}},
+		{query: "missing", expected: []string{}},
+		{query: "name[0]", expected: []string{}},
+	} {
+		t.Run(item.query, func(t *testing.T) {
+			found, err := Find(root, item.query)
+			require.NoError(t, err)
+			assert.Equal(t, item.expected, values(found))
+		})
+	}
+
+	t.Run("test spans", func(t *testing.T) {
+		found, err := Find(root, "span.end")
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		assert.Equal(t, strings.Index(input, "255"), found[0].Value.Span.Position)
+		assert.Equal(t, 1, found[0].Value.Span.Start.Line)
+	})
+
+	t.Run("test root", func(t *testing.T) {
+		found, err := Find(root, "")
+		require.NoError(t, err)
+		assert.Equal(t, []*Attribute{root}, found)
+	})
+
+	t.Run("test path conflict", func(t *testing.T) {
+		_, err := Find(MustParse(strings.NewReader("db=1, db.x=2")), "db")
+		var ce ConflictError
+		assert.ErrorAs(t, err, &ce)
+	})
+}
+
+func TestCompileQuery(t *testing.T) {
+	q, err := CompileQuery("users[*].username")
+	require.NoError(t, err)
+	assert.Equal(t, "users[*].username", q.String())
+
+	for _, query := range []string{".a", "a.", "a..b", "a[", "a[x]", "a[1]b", "a-b", "'unterminated", `'\q'`, "a.'x'y"} {
+		t.Run(query, func(t *testing.T) {
+			_, err := CompileQuery(query)
+			assert.ErrorIs(t, err, ErrInvalidQuery)
+		})
+	}
+}
@@ -1,6 +1,10 @@
 package parser
 
-import "fmt"
+import (
+	"fmt"
+	"sort"
+	"unicode/utf8"
+)
 
 func newSourceSpan(pos int, length ...int) *SourceSpan {
 	result := &SourceSpan{
@@ -12,9 +16,29 @@ func newSourceSpan(pos int, length ...int) *SourceSpan {
 	return result
 }
 
+// SourceSpan is part of parsed text. Position and Length are measured in runes (not bytes), Start and End
+// hold full location of span boundaries (End is exclusive). Start and End are filled by Parse, spans created
+// by hand have them zero (Line 0 means unknown location).
 type SourceSpan struct {
+	// Position is rune offset from start of input (0-based)
 	Position int
-	Length   int
+	// Length in runes
+	Length int
+
+	Start Location
+	End   Location
+}
+
+// Location in parsed text
+type Location struct {
+	// Offset is byte offset from start of input (0-based)
+	Offset int
+	// Rune is rune offset from start of input (0-based)
+	Rune int
+	// Line number (1-based), lines are separated by '\n'
+	Line int
+	// Column is rune offset from start of line (1-based)
+	Column int
 }
 
 func (s *SourceSpan) withLength(length int) *SourceSpan {
@@ -49,6 +73,95 @@ func (s *SourceSpan) incrLengthBy(by int) *SourceSpan {
 	}
 }
 
+// String returns position and length of span, it's part of ParseError.Error() text, so locations are not included
+// to keep the text stable (use Start and End, or ParseError.Line and Column).
 func (s *SourceSpan) String() string {
 	return fmt.Sprintf("Span[Position: %d, Length: %d]", s.Position, s.Length)
 }
+
+// newLocator returns locator for given input.
+func newLocator(content string) *locator {
+	result := &locator{
+		content: content,
+		lines:   []int{0},
+	}
+	ascii := len(content) == utf8.RuneCountInString(content)
+	if !ascii {
+		result.offsets = make([]int, 0, len(content))
+	}
+
+	index := 0
+	for offset, r := range content {
+		if !ascii {
+			result.offsets = append(result.offsets, offset)
+		}
+		index++
+		if r == '\n' {
+			result.lines = append(result.lines, index)
+		}
+	}
+	result.runes = index
+	return result
+}
+
+// locator resolves rune positions to full locations (byte offset, line and column)
+type locator struct {
+	content string
+	// rune offsets where lines start
+	lines []int
+	// byte offset of each rune, nil for ASCII input (byte offset equals rune offset)
+	offsets []int
+	// number of runes in content
+	runes int
+}
+
+// location returns location of rune at given position, position can be also end of input.
+func (l *locator) location(position int) Location {
+	position = max(0, min(position, l.runes))
+	line := sort.Search(len(l.lines), func(i int) bool { return l.lines[i] > position }) - 1
+
+	offset := position
+	if l.offsets != nil {
+		if position < len(l.offsets) {
+			offset = l.offsets[position]
+		} else {
+			offset = len(l.content)
+		}
+	}
+
+	return Location{
+		Offset: offset,
+		Rune:   position,
+		Line:   line + 1,
+		Column: position - l.lines[line] + 1,
+	}
+}
+
+// resolve fills Start and End of span
+func (l *locator) resolve(span *SourceSpan) {
+	if span == nil {
+		return
+	}
+	span.Start = l.location(span.Position)
+	span.End = l.location(span.Position + span.Length)
+}
+
+// resolveAttribute resolves spans of attribute and all its children
+func (l *locator) resolveAttribute(attr *Attribute) {
+	l.resolve(attr.Span)
+	if attr.Value != nil {
+		l.resolve(attr.Value.Span)
+	}
+	for _, attrs := range []*Attributes{attr.Object, attr.Array} {
+		if attrs == nil {
+			continue
+		}
+		l.resolve(attrs.Span)
+		for _, child := range attrs.Attributes {
+			l.resolveAttribute(child)
+		}
+	}
+	for _, comment := range attr.Comments {
+		l.resolve(comment.Span)
+	}
+}
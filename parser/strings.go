@@ -3,6 +3,7 @@ package parser
 import (
 	"fmt"
 	"regexp"
+	"strings"
 )
 
 var (
@@ -19,3 +20,91 @@ func ValidateIdentifier(input string) error {
 	}
 	return nil
 }
+
+// Suggest returns candidate closest to name by edit distance (Levenshtein, letter case is ignored), so typos like
+// maxlength or max_lenght suggest max_length. Empty string is returned when no candidate is close enough: distance
+// must be at most max(2, len(name)/3) and less than length of name. Ties are resolved by the smaller candidate.
+func Suggest(name string, candidates []string) string {
+	source := []rune(strings.ToLower(name))
+	limit := max(2, len(source)/3)
+
+	result, best := "", -1
+	for _, candidate := range candidates {
+		distance := editDistance(source, []rune(strings.ToLower(candidate)))
+		if distance > limit || distance >= len(source) {
+			continue
+		}
+		if best < 0 || distance < best || distance == best && candidate < result {
+			result, best = candidate, distance
+		}
+	}
+	return result
+}
+
+// editDistance returns Levenshtein distance of a and b
+func editDistance(a, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+// dedent removes common leading whitespace from all lines (same as Python's textwrap.dedent).
+// Lines that consist only of whitespace are emptied and don't count towards common indentation.
+func dedent(input string) string {
+	lines := strings.Split(input, "\n")
+
+	margin, found := "", false
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed == "\r" {
+			lines[i] = trimmed
+			continue
+		}
+		indent := line[:len(line)-len(trimmed)]
+		switch {
+		case !found:
+			margin, found = indent, true
+		case strings.HasPrefix(indent, margin):
+			// current margin is still common
+		case strings.HasPrefix(margin, indent):
+			margin = indent
+		default:
+			// find longest common prefix
+			n := 0
+			for n < len(margin) && n < len(indent) && margin[n] == indent[n] {
+				n++
+			}
+			margin = margin[:n]
+		}
+	}
+
+	if margin == "" {
+		return strings.Join(lines, "\n")
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimPrefix(line, margin)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// trimLeadingNewline removes single line break (\n or \r\n) from start of input.
+func trimLeadingNewline(input string) string {
+	if rest, ok := strings.CutPrefix(input, "\r\n"); ok {
+		return rest
+	}
+	return strings.TrimPrefix(input, "\n")
+}
@@ -0,0 +1,64 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDedent(t *testing.T) {
+	for _, item := range []struct {
+		input    string
+		expected string
+	}{
+		{input: "", expected: ""},
+		{input: "no indent\n  here", expected: "no indent\n  here"},
+		{input: "  a\n  b", expected: "a\nb"},
+		{input: "    a\n  b\n      c", expected: "  a\nb\n    c"},
+		{input: "  a\n\n  b\n     \n", expected: "a\n\nb\n\n"},
+		{input: "\ta\n\t\tb", expected: "a\n\tb"},
+		{input: "\t a\n\t  b", expected: "a\n b"},
+		{input: "  a\n\tb", expected: "  a\n\tb"},
+		{input: "  a\r\n  \r\n  b", expected: "a\r\n\r\nb"},
+	} {
+		assert.Equal(t, item.expected, dedent(item.input), "input: %q", item.input)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"max_length", "min_length", "name", "id", "label"}
+	for _, item := range []struct {
+		name     string
+		expected string
+	}{
+		{name: "maxlength", expected: "max_length"},
+		{name: "max_lenght", expected: "max_length"},
+		{name: "MaxLength", expected: "max_length"},
+		{name: "nmae", expected: "name"},
+		{name: "NAME", expected: "name"},
+		{name: "lable", expected: "label"},
+		{name: "ids", expected: "id"},
+		// ties are resolved by the smaller candidate
+		{name: "m_length", expected: "max_length"},
+		{name: "x", expected: ""},
+		{name: "ab", expected: ""},
+		{name: "description", expected: ""},
+	} {
+		t.Run(item.name, func(t *testing.T) {
+			assert.Equal(t, item.expected, Suggest(item.name, candidates))
+		})
+	}
+	assert.Equal(t, "", Suggest("name", nil))
+}
+
+func TestUnknownAttributeError(t *testing.T) {
+	err := NewUnknownAttributeError(newSourceSpan(3, 4), "nmae", "name")
+	assert.Equal(t, "unknown attribute nmae, did you mean name?", err.Message())
+	assert.Equal(t, "nmae", err.Name())
+	assert.Equal(t, "name", err.Suggestion())
+	assert.Equal(t, 3, err.Position())
+
+	err = NewUnknownAttributeError(newSourceSpan(0), "other", "")
+	assert.Equal(t, "unknown attribute other", err.Message())
+	assert.Empty(t, err.Suggestion())
+}
@@ -0,0 +1,240 @@
+package parser
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Syntax is front-end syntax of attribute strings. Every syntax produces the same AST as attribute grammar, so
+// legacy formats can be read by the same definitions. Options.Syntax selects syntax used by Parse.
+type Syntax interface {
+	// Parse parses whole input into top-level attribute (its Object holds all attributes). Spans are rune positions
+	// in input, their locations are resolved by Parse.
+	Parse(input string, options Options) (*Attribute, error)
+}
+
+// StructTagSyntax reads classic Go struct tag convention (reflect.StructTag): space-separated key:"value" pairs
+// where value is Go string literal (name:"user_id" size:"255" required:"true"). All values are strings and values
+// which are valid numbers or booleans (255, true) can be set also to number and boolean fields.
+type StructTagSyntax struct{}
+
+// Parse implements Syntax
+func (StructTagSyntax) Parse(input string, options Options) (*Attribute, error) {
+	s := newSyntaxScanner(input)
+	result := s.root()
+
+	for {
+		s.skipSpace()
+		if s.eof() {
+			break
+		}
+
+		start := s.pos
+		for !s.eof() && s.peek() > ' ' && s.peek() != ':' && s.peek() != '"' && s.peek() != 0x7f {
+			s.pos++
+		}
+		if s.pos == start {
+			return nil, NewParseError(newSourceSpan(start, 1), "expected key but got %q", s.peek())
+		}
+		key := s.text(start, s.pos)
+		if s.eof() || s.peek() != ':' {
+			return nil, NewParseError(newSourceSpan(s.pos), "expected ':' after key %q", key)
+		}
+		s.pos++
+
+		valueStart := s.pos
+		if s.eof() || s.peek() != '"' {
+			return nil, NewParseError(newSourceSpan(s.pos), "expected quoted value of %q", key)
+		}
+		for s.pos++; !s.eof() && s.peek() != '"'; s.pos++ {
+			if s.peek() == '\\' {
+				s.pos++
+			}
+		}
+		if s.eof() {
+			return nil, NewParseError(newSourceSpan(valueStart, s.pos-valueStart), "unterminated value of %q", key)
+		}
+		s.pos++
+		valueSpan := newSourceSpan(valueStart, s.pos-valueStart)
+		value, err := strconv.Unquote(s.text(valueStart, s.pos))
+		if err != nil {
+			return nil, NewParseError(valueSpan, "invalid value of %q: %v", key, err)
+		}
+		if !s.eof() && !unicode.IsSpace(s.peek()) {
+			return nil, NewParseError(newSourceSpan(s.pos), "expected space after value of %q", key)
+		}
+
+		attr := syntaxAttribute(key, newSourceSpan(start, s.pos-start))
+		attr.Value = scalarValue(value, valueSpan)
+		result.Object.Push(attr)
+	}
+
+	return s.finish(result), nil
+}
+
+// SemicolonSyntax reads semicolon-separated key=value pairs (name=user_id; size=255; required). Key without value
+// is bare flag, values are raw text with surrounding whitespace trimmed or quoted strings (same as in attribute
+// grammar) when they contain ';'. Values which are valid numbers or booleans can be set also to number and
+// boolean fields.
+type SemicolonSyntax struct {
+	// Separator between key and value, zero value means '=' (use ':' for column:name;type:text)
+	Separator rune
+}
+
+// Parse implements Syntax
+func (ss SemicolonSyntax) Parse(input string, options Options) (*Attribute, error) {
+	separator := ss.Separator
+	if separator == 0 {
+		separator = '='
+	}
+
+	s := newSyntaxScanner(input)
+	result := s.root()
+
+	for {
+		s.skipSpace()
+		if s.eof() {
+			break
+		}
+		// empty items (a=1;;b=2 or trailing ;) are skipped
+		if s.peek() == ';' {
+			s.pos++
+			continue
+		}
+
+		start := s.pos
+		for !s.eof() && s.peek() != separator && s.peek() != ';' {
+			s.pos++
+		}
+		key := strings.TrimRightFunc(s.text(start, s.pos), unicode.IsSpace)
+		if key == "" {
+			return nil, NewParseError(newSourceSpan(start), "expected key before %q", separator)
+		}
+
+		var attr *Attribute
+		if s.eof() || s.peek() == ';' {
+			// bare flag same as in attribute grammar
+			attr = syntaxAttribute(key, newSourceSpan(start, s.pos-start))
+			trueStr := "true"
+			attr.Value = &Value{Span: attr.Span, Boolean: &trueStr, String: &trueStr}
+			attr.Flag = true
+		} else {
+			s.pos++ // consume separator
+			s.skipSpace()
+			value, err := s.semicolonValue(options)
+			if err != nil {
+				return nil, err
+			}
+			attr = syntaxAttribute(key, newSourceSpan(start, s.pos-start))
+			attr.Value = value
+		}
+		result.Object.Push(attr)
+
+		s.skipSpace()
+		if !s.eof() && s.peek() != ';' {
+			return nil, NewParseError(newSourceSpan(s.pos), "expected ';' after value of %q", key)
+		}
+	}
+
+	return s.finish(result), nil
+}
+
+// semicolonValue reads quoted string or raw value up to ';'
+func (s *syntaxScanner) semicolonValue(options Options) (*Value, error) {
+	start := s.pos
+	if !s.eof() && strings.ContainsRune(`'"`+"`", s.peek()) {
+		l := newLexer(strings.NewReader(s.text(start, len(s.runes))), Options{DedentStrings: options.DedentStrings})
+		span, tok, val := l.Lex()
+		if tok == TokenError {
+			return nil, tok.AsError(span.withPosition(start+span.Position), val)
+		}
+		s.pos = start + span.Position + span.Length
+		return &Value{Span: newSourceSpan(start, s.pos-start), String: &val}, nil
+	}
+
+	for !s.eof() && s.peek() != ';' {
+		s.pos++
+	}
+	raw := strings.TrimRightFunc(s.text(start, s.pos), unicode.IsSpace)
+	return scalarValue(raw, newSourceSpan(start, len([]rune(raw)))), nil
+}
+
+// syntaxScanner holds input of syntax as runes, so positions are rune positions same as spans
+type syntaxScanner struct {
+	runes []rune
+	pos   int
+}
+
+func newSyntaxScanner(input string) *syntaxScanner {
+	return &syntaxScanner{runes: []rune(input)}
+}
+
+func (s *syntaxScanner) eof() bool {
+	return s.pos >= len(s.runes)
+}
+
+func (s *syntaxScanner) peek() rune {
+	if s.eof() {
+		return 0
+	}
+	return s.runes[s.pos]
+}
+
+func (s *syntaxScanner) skipSpace() {
+	for !s.eof() && unicode.IsSpace(s.peek()) {
+		s.pos++
+	}
+}
+
+func (s *syntaxScanner) text(start, end int) string {
+	return string(s.runes[start:end])
+}
+
+// root returns empty top-level attribute
+func (s *syntaxScanner) root() *Attribute {
+	return &Attribute{Span: newSourceSpan(0), Object: newAttributes(newSourceSpan(0))}
+}
+
+// finish sets spans of top-level attribute to whole input
+func (s *syntaxScanner) finish(result *Attribute) *Attribute {
+	result.Span.Length = len(s.runes)
+	result.Object.Span.Length = len(s.runes)
+	return result
+}
+
+// syntaxAttribute returns attribute named by key, dotted keys (db.pool.max) are paths same as in attribute grammar
+// and keys which are not identifiers (Content-Type) are quoted.
+func syntaxAttribute(key string, span *SourceSpan) *Attribute {
+	result := &Attribute{Name: key, Span: span}
+	segments := strings.Split(key, ".")
+	for _, segment := range segments {
+		if !isIdentifier(segment) {
+			result.Quoted = true
+			return result
+		}
+	}
+	if len(segments) > 1 {
+		result.Path = segments
+	}
+	return result
+}
+
+// scalarValue returns string value which is also number or boolean when text is valid number literal or boolean,
+// same as identifier true and literal -Inf in attribute grammar.
+func scalarValue(text string, span *SourceSpan) *Value {
+	result := &Value{Span: span, String: &text}
+	if text == "true" || text == "false" {
+		result.Boolean = &text
+	}
+	if isNumberLiteral(text) {
+		result.Number = &text
+	}
+	return result
+}
+
+// TextValue returns value of raw text (environment variable, command-line flag) the same way as syntaxes read
+// values: it's string which is also number or boolean when text is valid number literal or boolean.
+func TextValue(text string) *Value {
+	return scalarValue(text, newSourceSpan(0, len([]rune(text))))
+}
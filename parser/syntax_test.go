@@ -0,0 +1,151 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStructTagSyntax(t *testing.T) {
+	options := Options{Syntax: StructTagSyntax{}}
+
+	t.Run("test parse", func(t *testing.T) {
+		input := `name:"user_id" size:"255"  required:"true" label:"Hello \"world\"" db.pool.max:"10" Content-Type:"json" empty:""`
+		result, err := Parse(strings.NewReader(input), options)
+		require.NoError(t, err)
+		assert.Equal(t, `name=user_id, size=255, required=true, label='Hello "world"', db.pool.max=10, 'Content-Type'=json, empty=''`,
+			Print(result, PrintOptions{}))
+
+		// numbers and booleans are also strings
+		size := result.Object.Attributes[1].Value
+		assert.Equal(t, "255", *size.String)
+		assert.Equal(t, "255", *size.Number)
+		assert.Equal(t, "true", *result.Object.Attributes[2].Value.String)
+	})
+
+	t.Run("test spans", func(t *testing.T) {
+		result, err := Parse(strings.NewReader("ž:\"a\"\n  size:\"0x10\""), options)
+		require.NoError(t, err)
+		size := result.Object.Attributes[1]
+		assert.Equal(t, 2, size.Span.Start.Line)
+		assert.Equal(t, 3, size.Span.Start.Column)
+		assert.Equal(t, 13, size.Value.Span.Position)
+		assert.Equal(t, 6, size.Value.Span.Length)
+		assert.Equal(t, 2, result.Object.Attributes[0].Value.Span.Position)
+	})
+
+	t.Run("test empty", func(t *testing.T) {
+		result, err := Parse(strings.NewReader("  "), options)
+		require.NoError(t, err)
+		assert.Empty(t, result.Object.Attributes)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+			position int
+		}{
+			{input: `:"x"`, expected: `expected key but got ':'`, position: 0},
+			{input: `name`, expected: `expected ':' after key "name"`, position: 4},
+			{input: `name "x"`, expected: `expected ':' after key "name"`, position: 4},
+			{input: `name:x`, expected: `expected quoted value of "name"`, position: 5},
+			{input: `name:"x`, expected: `unterminated value of "name"`, position: 5},
+			{input: `name:"x\"`, expected: `unterminated value of "name"`, position: 5},
+			{input: `name:"\q"`, expected: `invalid value of "name"`, position: 5},
+			{input: `a:"1"b:"2"`, expected: `expected space after value of "a"`, position: 5},
+			{input: "a:\"1\"\tb:\"2\"\nc:\"3\"", expected: ""},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				_, err := Parse(strings.NewReader(item.input), options)
+				if item.expected == "" {
+					assert.NoError(t, err)
+					return
+				}
+				var pe ParseError
+				require.ErrorAs(t, err, &pe)
+				assert.Contains(t, pe.Message(), item.expected)
+				assert.Equal(t, item.position, pe.Position())
+			})
+		}
+	})
+}
+
+func TestSemicolonSyntax(t *testing.T) {
+	options := Options{Syntax: SemicolonSyntax{}}
+
+	t.Run("test parse", func(t *testing.T) {
+		input := ` name = user id ; size=255;required; label="a; b" ;;db.pool.max=1.5;Content-Type=json;empty=;`
+		result, err := Parse(strings.NewReader(input), options)
+		require.NoError(t, err)
+		assert.Equal(t, `name='user id', size=255, required, label='a; b', db.pool.max=1.5, 'Content-Type'=json, empty=''`,
+			Print(result, PrintOptions{}))
+	})
+
+	t.Run("test separator", func(t *testing.T) {
+		result, err := Parse(strings.NewReader("column:name;type:varchar(100);not null"), Options{Syntax: SemicolonSyntax{Separator: ':'}})
+		require.NoError(t, err)
+		assert.Equal(t, `column=name, type='varchar(100)', 'not null'`, Print(result, PrintOptions{}))
+		notNull := result.Object.Attributes[2]
+		assert.Equal(t, "not null", notNull.Name)
+		assert.True(t, notNull.Quoted)
+		assert.True(t, notNull.Flag)
+	})
+
+	t.Run("test spans", func(t *testing.T) {
+		result, err := Parse(strings.NewReader("a=1; b = 'x' ; c = raw value  "), options)
+		require.NoError(t, err)
+		b := result.Object.Attributes[1]
+		assert.Equal(t, 5, b.Span.Position)
+		assert.Equal(t, 7, b.Span.Length)
+		assert.Equal(t, 9, b.Value.Span.Position)
+		assert.Equal(t, 3, b.Value.Span.Length)
+		c := result.Object.Attributes[2].Value
+		assert.Equal(t, 19, c.Span.Position)
+		assert.Equal(t, 9, c.Span.Length)
+	})
+
+	t.Run("test errors", func(t *testing.T) {
+		for _, item := range []struct {
+			input    string
+			expected string
+			position int
+		}{
+			{input: `=1`, expected: `expected key before '='`, position: 0},
+			{input: `a=1; =2`, expected: `expected key before '='`, position: 5},
+			{input: `a='x' b`, expected: `expected ';' after value of "a"`, position: 6},
+			{input: `a='x`, expected: "unterminated", position: 4},
+		} {
+			t.Run(item.input, func(t *testing.T) {
+				_, err := Parse(strings.NewReader(item.input), options)
+				var pe ParseError
+				require.ErrorAs(t, err, &pe)
+				assert.Contains(t, pe.Message(), item.expected)
+				assert.Equal(t, item.position, pe.Position())
+			})
+		}
+	})
+
+	t.Run("test cst", func(t *testing.T) {
+		_, err := ParseCST(strings.NewReader("a=1"), options)
+		assert.ErrorIs(t, err, ErrUnsupportedSyntax)
+	})
+}
+
+func TestTextValue(t *testing.T) {
+	value := TextValue("0x10")
+	assert.Equal(t, "0x10", *value.String)
+	assert.Equal(t, "0x10", *value.Number)
+	assert.Nil(t, value.Boolean)
+	assert.Equal(t, 4, value.Span.Length)
+
+	value = TextValue("false")
+	assert.Equal(t, "false", *value.Boolean)
+	assert.Nil(t, value.Number)
+
+	value = TextValue("a b")
+	assert.Equal(t, "a b", *value.String)
+	assert.Nil(t, value.Number)
+}
@@ -15,6 +15,7 @@ const (
 	TokenOpenSquareBracket
 	TokenCloseSquareBracket
 	TokenError
+	TokenDot
 )
 
 func (t Token) String() string {
@@ -41,6 +42,8 @@ func (t Token) String() string {
 		return "CLOSE_SQUARE_BRACKET"
 	case TokenError:
 		return "ERROR"
+	case TokenDot:
+		return "DOT"
 	default:
 		return "UNKNOWN"
 	}
@@ -38,17 +38,20 @@ func (v ValueType) FromRaw(raw string, val *Value) error {
 // Value represents val of attribute
 // - Number represents any number int/float
 // - String represents: string, bool
+// - Null represents null/nil literal (explicit unset)
 type Value struct {
 	Span    *SourceSpan
 	Boolean *string
 	Number  *string
 	String  *string
+	Null    bool
 }
 
 func (v *Value) ClearAll() {
 	v.Boolean = nil
 	v.Number = nil
 	v.String = nil
+	v.Null = false
 }
 
 func (v *Value) FromRaw(valueType ValueType, raw string) error {
@@ -57,6 +60,8 @@ func (v *Value) FromRaw(valueType ValueType, raw string) error {
 
 func (v *Value) BuildValue() (reflect.Value, error) {
 	switch {
+	case v.Null:
+		return reflect.Zero(reflect.TypeOf((*any)(nil)).Elem()), nil
 	case v.Boolean != nil:
 		b, err := strconv.ParseBool(*v.Boolean)
 		if err != nil {
@@ -69,9 +74,9 @@ func (v *Value) BuildValue() (reflect.Value, error) {
 		target.SetBool(b)
 		return target, nil
 	case v.Number != nil:
-		r, err := strconv.ParseInt(*v.Number, 10, 64)
+		r, err := ParseInt(*v.Number, 64)
 		if err != nil {
-			r, err := strconv.ParseFloat(*v.Number, 64)
+			r, err := ParseFloat(*v.Number, 64)
 			if err != nil {
 				return reflect.Value{}, NewParseError(v.Span, "invalid number value: %s", *v.Number)
 			}
@@ -103,7 +108,7 @@ func (v *Value) BuildValue() (reflect.Value, error) {
 }
 
 func (v *Value) IsZero() bool {
-	return v.Boolean == nil && v.Number == nil && v.String == nil
+	return !v.Null && v.Boolean == nil && v.Number == nil && v.String == nil
 }
 
 func (v *Value) AsBool() (bool, error) {
@@ -118,7 +123,7 @@ func (v *Value) AsBool() (bool, error) {
 
 func (v *Value) AsInt() (int, error) {
 	if v.Number != nil {
-		parsed, err := strconv.ParseInt(*v.Number, 10, 64)
+		parsed, err := ParseInt(*v.Number, strconv.IntSize)
 		if err != nil {
 			return 0, NewParseError(v.Span, "invalid number value: %s", *v.Number)
 		}
@@ -129,7 +134,7 @@ func (v *Value) AsInt() (int, error) {
 
 func (v *Value) AsFloat() (float64, error) {
 	if v.Number != nil {
-		parsed, err := strconv.ParseFloat(*v.Number, 64)
+		parsed, err := ParseFloat(*v.Number, 64)
 		if err != nil {
 			return 0, NewParseError(v.Span, "invalid number value: %s", *v.Number)
 		}
@@ -172,3 +177,8 @@ func (v *Value) IsString() bool {
 	_, err := v.AsString()
 	return err == nil
 }
+
+// isNull returns whether ident is null literal.
+func isNull(ident string) bool {
+	return ident == "null" || ident == "nil"
+}
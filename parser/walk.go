@@ -0,0 +1,68 @@
+package parser
+
+import "errors"
+
+var (
+	// SkipChildren returned from Visitor.Enter skips children of attribute (Leave is still called)
+	SkipChildren = errors.New("skip children")
+	// SkipAll returned from Visitor stops walking, Walk returns nil
+	SkipAll = errors.New("skip all")
+)
+
+// Visitor visits attributes in Walk. Enter is called before children of attribute (object attributes and array
+// items) are visited and Leave after them. Error other than SkipChildren and SkipAll stops walking and is
+// returned by Walk.
+type Visitor interface {
+	Enter(attr *Attribute) error
+	Leave(attr *Attribute) error
+}
+
+// VisitorFuncs implements Visitor with functions, nil function does nothing
+type VisitorFuncs struct {
+	EnterFunc func(attr *Attribute) error
+	LeaveFunc func(attr *Attribute) error
+}
+
+func (v VisitorFuncs) Enter(attr *Attribute) error {
+	if v.EnterFunc == nil {
+		return nil
+	}
+	return v.EnterFunc(attr)
+}
+
+func (v VisitorFuncs) Leave(attr *Attribute) error {
+	if v.LeaveFunc == nil {
+		return nil
+	}
+	return v.LeaveFunc(attr)
+}
+
+// Walk visits attribute and all its children depth first in order they were written. Dotted paths are not
+// expanded, use Find to query them as nested attributes.
+func Walk(attr *Attribute, visitor Visitor) error {
+	if err := walk(attr, visitor); err != nil && err != SkipAll {
+		return err
+	}
+	return nil
+}
+
+func walk(attr *Attribute, visitor Visitor) error {
+	err := visitor.Enter(attr)
+	switch err {
+	case nil:
+		for _, attrs := range []*Attributes{attr.Object, attr.Array} {
+			if attrs == nil {
+				continue
+			}
+			for _, child := range attrs.Attributes {
+				if err := walk(child, visitor); err != nil {
+					return err
+				}
+			}
+		}
+	case SkipChildren:
+	default:
+		return err
+	}
+	return visitor.Leave(attr)
+}
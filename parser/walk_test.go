@@ -0,0 +1,86 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalk(t *testing.T) {
+	root := MustParse(strings.NewReader("a=1, b(c=2, d[3, (e=4)]), f"))
+
+	// name of attribute or value of unnamed one
+	label := func(attr *Attribute) string {
+		switch {
+		case attr == root:
+			return "root"
+		case attr.Name != "":
+			return attr.Name
+		case attr.Value != nil:
+			return *attr.Value.Number
+		case attr.Object != nil:
+			return "()"
+		}
+		return "?"
+	}
+
+	t.Run("test order", func(t *testing.T) {
+		var events []string
+		err := Walk(root, VisitorFuncs{
+			EnterFunc: func(attr *Attribute) error {
+				events = append(events, "+"+label(attr))
+				return nil
+			},
+			LeaveFunc: func(attr *Attribute) error {
+				events = append(events, "-"+label(attr))
+				return nil
+			},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"+root", "+a", "-a", "+b", "+c", "-c", "+d", "+3", "-3", "+()", "+e", "-e", "-()", "-d", "-b", "+f", "-f", "-root",
+		}, events)
+	})
+
+	t.Run("test skip children", func(t *testing.T) {
+		var entered []string
+		err := Walk(root, VisitorFuncs{EnterFunc: func(attr *Attribute) error {
+			entered = append(entered, label(attr))
+			if attr.Name == "b" {
+				return SkipChildren
+			}
+			return nil
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"root", "a", "b", "f"}, entered)
+	})
+
+	t.Run("test skip all", func(t *testing.T) {
+		var entered []string
+		err := Walk(root, VisitorFuncs{EnterFunc: func(attr *Attribute) error {
+			entered = append(entered, label(attr))
+			if attr.Name == "c" {
+				return SkipAll
+			}
+			return nil
+		}})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"root", "a", "b", "c"}, entered)
+	})
+
+	t.Run("test error", func(t *testing.T) {
+		expected := errors.New("stop")
+		var left []string
+		err := Walk(root, VisitorFuncs{LeaveFunc: func(attr *Attribute) error {
+			left = append(left, label(attr))
+			if attr.Name == "c" {
+				return expected
+			}
+			return nil
+		}})
+		assert.ErrorIs(t, err, expected)
+		assert.Equal(t, []string{"a", "c"}, left)
+	})
+}
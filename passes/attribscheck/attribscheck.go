@@ -0,0 +1,204 @@
+// Package attribscheck defines an Analyzer that validates attribs struct tags at vet time.
+//
+// It checks attr:"..." tags with the same rules attribs.New uses, so tag errors are reported by go vet instead of
+// at runtime. Tags consumed by attribs.Definition (e.g. mytag:"name=x, ...") can be checked too, the definition
+// has to be compiled into the vet tool:
+//
+//	func main() {
+//		singlechecker.Main(attribscheck.NewAnalyzer(attribscheck.Consumer{
+//			Key:        "mytag",
+//			Definition: attribs.Must(attribs.New(MyAttrs{})),
+//		}))
+//	}
+//
+// Diagnostics point at the exact place in tag, and suggested fixes are offered for stray commas, invalid
+// attribute names and misspelled names of unknown attributes.
+package attribscheck
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"regexp"
+	"strings"
+
+	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/parser"
+	"github.com/phonkee/attribs/tagsrc"
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/analysis/passes/inspect"
+	"golang.org/x/tools/go/ast/inspector"
+)
+
+const Doc = `check attribs struct tags
+
+The attribscheck analyzer reports attr struct tags that attribs.New would reject
+(syntax errors, missing or invalid name, non-boolean required or disabled, ...)
+and tags of configured consumers that their attribs.Definition cannot parse.`
+
+// Analyzer checks attr struct tags, use NewAnalyzer to check consumer tags as well
+var Analyzer = NewAnalyzer()
+
+// Checker checks tag value, attribs.Definition implements it
+type Checker interface {
+	Check(input string, ignoreUnknown bool) error
+}
+
+// Consumer is struct tag key with attribute string parsed by Definition
+type Consumer struct {
+	// Key of struct tag (e.g. mytag)
+	Key string
+
+	// Definition that parses tag value (usually attribs.Definition)
+	Definition Checker
+
+	// IgnoreUnknown is passed to Definition, unknown attributes are reported when false
+	IgnoreUnknown bool
+}
+
+// NewAnalyzer returns analyzer that checks attr tags and tags of given consumers
+func NewAnalyzer(consumers ...Consumer) *analysis.Analyzer {
+	c := &checker{consumers: consumers}
+	return &analysis.Analyzer{
+		Name:     "attribscheck",
+		Doc:      Doc,
+		URL:      "https://pkg.go.dev/github.com/phonkee/attribs/passes/attribscheck",
+		Requires: []*analysis.Analyzer{inspect.Analyzer},
+		Run:      c.run,
+	}
+}
+
+type checker struct {
+	consumers []Consumer
+}
+
+func (c *checker) run(pass *analysis.Pass) (any, error) {
+	insp := pass.ResultOf[inspect.Analyzer].(*inspector.Inspector)
+
+	insp.Preorder([]ast.Node{(*ast.StructType)(nil)}, func(node ast.Node) {
+		for _, field := range node.(*ast.StructType).Fields.List {
+			if field.Tag == nil {
+				continue
+			}
+			c.checkTag(pass, field, attribs.TagName, attribs.ValidateTag)
+			for _, consumer := range c.consumers {
+				c.checkTag(pass, field, consumer.Key, func(value string) error {
+					return consumer.Definition.Check(value, consumer.IgnoreUnknown)
+				})
+			}
+		}
+	})
+
+	return nil, nil
+}
+
+// checkTag checks value of given tag key in field with check function and reports problem
+func (c *checker) checkTag(pass *analysis.Pass, field *ast.Field, key string, check func(string) error) {
+	// malformed struct tags are reported by structtag analyzer
+	tag, err := tagsrc.FromField(field, key)
+	if err != nil || tag == nil {
+		return
+	}
+
+	err = check(tag.Value)
+	if err == nil {
+		return
+	}
+
+	diagnostic := analysis.Diagnostic{
+		Pos:     field.Tag.Pos(),
+		End:     field.Tag.End(),
+		Message: fmt.Sprintf("invalid %s tag: %v", key, err),
+	}
+
+	var pe parser.ParseError
+	if errors.As(err, &pe) {
+		diagnostic.Pos, diagnostic.End = tag.SpanPos(pe.Span())
+		if diagnostic.End <= diagnostic.Pos {
+			diagnostic.End = token.NoPos
+		}
+		// message of top level error has location in source already
+		if _, direct := err.(parser.ParseError); direct {
+			diagnostic.Message = fmt.Sprintf("invalid %s tag: %s", key, pe.Message())
+		}
+		diagnostic.SuggestedFixes = suggestFixes(tag, pe, check)
+	}
+
+	pass.Report(diagnostic)
+}
+
+// suggestFixes returns fixes for problem in tag, every fix is checked that it resolves the problem.
+func suggestFixes(tag *tagsrc.Tag, pe parser.ParseError, check func(string) error) []analysis.SuggestedFix {
+	// fixes need byte offsets, spans produced by parser have them resolved
+	if pe.Span().Start.Line == 0 {
+		return nil
+	}
+	value := tag.Value
+	offset := pe.Span().Start.Offset
+
+	// stray comma (trailing or double), error points right before it
+	comma := offset + len(value[offset:]) - len(strings.TrimLeft(value[offset:], " \t\r\n"))
+	if comma < len(value) && value[comma] == ',' && fixes(pe, check, value[:comma]+value[comma+1:]) {
+		return []analysis.SuggestedFix{{
+			Message:   "Remove comma",
+			TextEdits: []analysis.TextEdit{{Pos: tag.Pos(comma), End: tag.Pos(comma + 1)}},
+		}}
+	}
+
+	// unknown attribute with close defined name, rename it (quoted keys and paths are left alone)
+	if ue, ok := pe.(parser.UnknownAttributeError); ok && ue.Suggestion() != "" {
+		span := pe.Span()
+		fixed := value[:span.Start.Offset] + ue.Suggestion() + value[span.End.Offset:]
+		if value[span.Start.Offset:span.End.Offset] == ue.Name() && fixes(pe, check, fixed) {
+			start, end := tag.SpanPos(span)
+			return []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Rename to %s", ue.Suggestion()),
+				TextEdits: []analysis.TextEdit{{Pos: start, End: end, NewText: []byte(ue.Suggestion())}},
+			}}
+		}
+	}
+
+	// invalid attribute name, replace it with identifier
+	if name, ok := invalidName(value, pe); ok {
+		span := pe.Span()
+		fixed := value[:span.Start.Offset] + name + value[span.End.Offset:]
+		if check(fixed) == nil {
+			start, end := tag.SpanPos(span)
+			return []analysis.SuggestedFix{{
+				Message:   fmt.Sprintf("Rename to %s", name),
+				TextEdits: []analysis.TextEdit{{Pos: start, End: end, NewText: []byte(name)}},
+			}}
+		}
+	}
+
+	return nil
+}
+
+// fixes returns whether fixed value resolves problem (value is valid or error is at other place)
+func fixes(pe parser.ParseError, check func(string) error, fixed string) bool {
+	err := check(fixed)
+	var other parser.ParseError
+	return err == nil || errors.As(err, &other) && other.Position() > pe.Position()
+}
+
+var nonIdentifier = regexp.MustCompile(`[^a-zA-Z0-9_]+`)
+
+// invalidName returns identifier for name value the error points to
+func invalidName(value string, pe parser.ParseError) (string, bool) {
+	root, err := parser.Parse(strings.NewReader(value))
+	if err != nil {
+		return "", false
+	}
+	for _, attr := range root.Object.Attributes {
+		if attr.Name != "name" || attr.Value == nil || attr.Value.String == nil || attr.Value.Span.Position != pe.Position() {
+			continue
+		}
+		name := strings.Trim(nonIdentifier.ReplaceAllString(strings.TrimSpace(*attr.Value.String), "_"), "_")
+		if name == "" || parser.ValidateIdentifier(name) != nil {
+			return "", false
+		}
+		return name, true
+	}
+	return "", false
+}
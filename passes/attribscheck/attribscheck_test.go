@@ -0,0 +1,49 @@
+package attribscheck_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/phonkee/attribs"
+	"github.com/phonkee/attribs/passes/attribscheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+type column struct {
+	Column  string `attr:"name=column"`
+	Primary bool   `attr:"name=primary"`
+	Size    uint16 `attr:"name=size"`
+}
+
+func TestAnalyzer(t *testing.T) {
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), attribscheck.Analyzer, "a")
+}
+
+func TestAnalyzerConsumer(t *testing.T) {
+	analyzer := attribscheck.NewAnalyzer(attribscheck.Consumer{
+		Key:        "db",
+		Definition: attribs.Must(attribs.New(column{})),
+	})
+	analysistest.RunWithSuggestedFixes(t, analysistest.TestData(), analyzer, "consumer")
+}
+
+func TestAnalyzerPositions(t *testing.T) {
+	results := analysistest.Run(t, analysistest.TestData(), attribscheck.Analyzer, "a")
+	require.Len(t, results, 1)
+
+	// text in source where diagnostic starts, in order of fields
+	expected := []string{`required"`, `'my-field'`, `,"`, `, required"`, `yes"`, `)"`, `'a-b'\"`, `maybe"`}
+
+	fset := results[0].Pass.Fset
+	require.Len(t, results[0].Diagnostics, len(expected))
+	for i, diagnostic := range results[0].Diagnostics {
+		position := fset.Position(diagnostic.Pos)
+		file := fset.File(diagnostic.Pos)
+		content, err := results[0].Pass.ReadFile(file.Name())
+		require.NoError(t, err)
+		rest := string(content[position.Offset:])
+		assert.True(t, strings.HasPrefix(rest, expected[i]), "diagnostic %q points to %q", diagnostic.Message, strings.SplitN(rest, "\n", 2)[0])
+	}
+}
@@ -0,0 +1,22 @@
+package a
+
+type Valid struct {
+	Name  string `attr:"name=name"`
+	Count int    `json:"count" attr:"name=count, required"`
+	Pos   string `attr:"name=pos, pos=0"`
+	Skip  string
+	Other string `json:"other"`
+}
+
+type Invalid struct {
+	Missing  string `attr:"required"`                     // want `invalid attr tag: attribute name is required`
+	Dash     string `attr:"name='my-field'"`              // want `invalid attr tag: invalid attribute name: my-field`
+	Trailing string `attr:"name=trailing,"`               // want `invalid attr tag: trailing comma not allowed`
+	Double   string `attr:"name=double,, required"`       // want `invalid attr tag: unexpected double comma`
+	Required string `attr:"name=required, required=yes"` // want `invalid attr tag: invalid tag: required not boolean`
+	Syntax   string `attr:"name=)"`                       // want `invalid attr tag: expected value`
+	Escaped  string "json:\"escaped\" attr:\"name='a-b'\"" // want `invalid attr tag: invalid attribute name: a-b`
+	Nested   struct {
+		Inner string `attr:"name=inner, disabled=maybe"` // want `invalid attr tag: .*disabled not boolean`
+	}
+}
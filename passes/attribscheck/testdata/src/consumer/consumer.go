@@ -0,0 +1,11 @@
+package consumer
+
+type Model struct {
+	ID      int    `attr:"name=id" db:"column=id, primary"`
+	Name    string `attr:"name=name" db:"column=name, size=255"`
+	Size    string `db:"column=size, size=-1"`    // want `invalid db tag: value -1 out of range for uint16: 0\.\.65535`
+	Unknown string `db:"column=unknown, nope"`    // want `invalid db tag: unknown attribute nope`
+	Typo    string `db:"column=typo, primray"`     // want `invalid db tag: unknown attribute primray, did you mean primary\?`
+	Comma   string `db:"column=comma,, primary"` // want `invalid db tag: unexpected double comma`
+	Other   string `json:"other"`
+}
@@ -2,6 +2,7 @@ package attribs
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/phonkee/attribs/parser"
@@ -20,42 +21,170 @@ func parseAttribsTag(tag string, skipUnknown bool) (result attrAttribs, _ error)
 		return result, nil
 	}
 
+	// span of name value, so validation error can point to it
+	nameSpan := parsed.Span
+
 	for _, attr := range parsed.Object.Attributes {
+		// attributes without value (objects, arrays) are invalid for all known keys
+		if attr.Value == nil {
+			switch attr.Name {
+			case "name", "disabled", "required", "pos", "help":
+				return result, newTagError(attr.Span, fmt.Errorf("%w: %s must be a value", ErrInvalidTag, attr.Name))
+			}
+		}
+
 		switch attr.Name {
 		case "name":
 			if result.Name, err = attr.Value.AsTrimmedString(); err != nil {
-				return result, fmt.Errorf("invalid name: %w", ErrInvalidTag)
+				return result, newTagError(attr.Value.Span, fmt.Errorf("invalid name: %w", ErrInvalidTag))
 			}
 			result.Alias = result.Name
+			nameSpan = attr.Value.Span
 		case "disabled":
 			if result.Disabled, err = attr.Value.AsBool(); err != nil {
-				return result, fmt.Errorf("%w: disabled not boolean", err)
+				return result, newTagError(attr.Value.Span, fmt.Errorf("%w: disabled not boolean", err))
 			}
 		case "required":
 			if result.Required, err = attr.Value.AsBool(); err != nil {
-				return result, fmt.Errorf("%w: required not boolean", ErrInvalidTag)
+				return result, newTagError(attr.Value.Span, fmt.Errorf("%w: required not boolean", ErrInvalidTag))
 			}
 		case "pos":
 			pos, err := attr.Value.AsInt()
 			if err != nil {
-				return result, fmt.Errorf("%w: pos must be an integer", ErrInvalidTag)
+				return result, newTagError(attr.Value.Span, fmt.Errorf("%w: pos must be an integer", ErrInvalidTag))
 			}
 			result.Position = pos
 			result.IsPositional = true
+		case "help":
+			if result.Help, err = attr.Value.AsString(); err != nil {
+				return result, newTagError(attr.Value.Span, fmt.Errorf("%w: help must be a string", ErrInvalidTag))
+			}
+		case "default":
+			result.Default = printUnnamed(attr)
+		case "enum":
+			if attr.Array == nil {
+				return result, newTagError(attr.Span, fmt.Errorf("%w: enum must be an array", ErrInvalidTag))
+			}
+			for _, item := range attr.Array.Attributes {
+				if item.Value == nil {
+					return result, newTagError(item.Span, fmt.Errorf("%w: enum values must be scalars", ErrInvalidTag))
+				}
+				result.Enum = append(result.Enum, printUnnamed(item))
+			}
 		default:
 			if !skipUnknown {
-				return result, fmt.Errorf("%w: %v", ErrInvalidTag, attr.Name)
+				return result, newTagError(attr.Span, fmt.Errorf("%w: %v", ErrInvalidTag, attr.Name))
 			}
 		}
 	}
 
 	if err = result.Validate(); err != nil {
-		return result, err
+		return result, newTagError(nameSpan, err)
 	}
 
 	return result, nil
 }
 
+// fieldAttribs returns attribs of struct field from attr tag. Fields without attr tag take name from nameSource tag
+// (json:"max_length,omitempty") when it's set, options after comma are ignored. Fields named "-" and fields whose
+// names are not valid attribute names (content-type, or "-" itself in json:"-,") are disabled, so they don't make
+// the whole struct invalid.
+func fieldAttribs(field reflect.StructField, nameSource string) (attrAttribs, error) {
+	return structTagAttribs(field.Tag, TagName, nameSource)
+}
+
+// structTagAttribs returns attribs from struct tags, attribute tag is under key
+func structTagAttribs(tags reflect.StructTag, key, nameSource string) (attrAttribs, error) {
+	if tag, ok := tags.Lookup(key); ok {
+		return parseAttribsTag(tag, true)
+	}
+
+	result := attrAttribs{Position: -1}
+	if nameSource == "" {
+		return result, nil
+	}
+	tag, ok := tags.Lookup(nameSource)
+	if !ok {
+		return result, nil
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	if name == "" {
+		return result, nil
+	}
+	if parser.ValidateIdentifier(name) != nil {
+		result.Disabled = true
+		return result, nil
+	}
+	result.Name = name
+	result.Alias = name
+	return result, nil
+}
+
+// ValidateTag checks attr struct tag with the same rules New uses. Returned error implements parser.ParseError
+// and points to the problem in tag.
+func ValidateTag(tag string) error {
+	_, err := parseAttribsTag(tag, true)
+	return err
+}
+
+// TagOptions are options of struct field read from its tags, tools that read struct tags from source map fields
+// with them the same way New does
+type TagOptions struct {
+	// Name of attribute, empty when field keeps its Go name
+	Name string
+
+	// Disabled field is not mapped (disabled=true, "-" or invalid name in name source tag)
+	Disabled bool
+
+	// Position of positional field, -1 when field is not positional
+	Position int
+
+	// documentation of attribute (required, help, default and enum), see Definition.Usage
+	Required bool
+	Help     string
+	Default  string
+	Enum     []string
+}
+
+// ParseTag returns options of field from attr tag value with the same rules New uses, errors are the same as
+// errors of ValidateTag.
+func ParseTag(tag string) (TagOptions, error) {
+	result, err := parseAttribsTag(tag, true)
+	return result.tagOptions(), err
+}
+
+// ParseStructTag returns options of field from its struct tags: tag under key (TagName for New) is parsed by
+// ParseTag, fields without it take name from nameSource tag same as Options.NameSource.
+func ParseStructTag(tags reflect.StructTag, key, nameSource string) (TagOptions, error) {
+	result, err := structTagAttribs(tags, key, nameSource)
+	return result.tagOptions(), err
+}
+
+// tagError is error in attr struct tag, it keeps original error (and its message) and adds span of the problem
+type tagError struct {
+	parser.ParseError
+	err error
+}
+
+func newTagError(span *parser.SourceSpan, err error) error {
+	return tagError{
+		ParseError: parser.NewParseError(span, "%v", err),
+		err:        err,
+	}
+}
+
+func (t tagError) Error() string {
+	return t.err.Error()
+}
+
+func (t tagError) Message() string {
+	return t.err.Error()
+}
+
+func (t tagError) Unwrap() error {
+	return t.err
+}
+
 // attrAttribs holds information about defined attribute
 type attrAttribs struct {
 	Alias        string
@@ -64,6 +193,38 @@ type attrAttribs struct {
 	Required     bool
 	Position     int // -1 = not positional
 	IsPositional bool
+
+	// documentation of attribute, used by Usage
+	Help    string
+	Default string
+	Enum    []string
+}
+
+// printUnnamed returns value of attribute as it's written in attribute string (5, 'text', [a, b])
+func printUnnamed(a *parser.Attribute) string {
+	unnamed := *a
+	unnamed.Name, unnamed.Path, unnamed.Quoted, unnamed.Flag = "", nil, false, false
+	// printed as positional value of top-level list, so objects keep their parentheses
+	root := &parser.Attribute{Object: &parser.Attributes{}}
+	root.Object.Push(&unnamed)
+	return parser.Print(root, parser.PrintOptions{})
+}
+
+// tagOptions returns exported form of attribs
+func (a attrAttribs) tagOptions() TagOptions {
+	result := TagOptions{
+		Name:     a.Name,
+		Disabled: a.Disabled,
+		Position: -1,
+		Required: a.Required,
+		Help:     a.Help,
+		Default:  a.Default,
+		Enum:     a.Enum,
+	}
+	if a.IsPositional {
+		result.Position = a.Position
+	}
+	return result
 }
 
 func (a attrAttribs) Validate() error {
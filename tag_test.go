@@ -1,8 +1,10 @@
 package attribs
 
 import (
+	"reflect"
 	"testing"
 
+	"github.com/phonkee/attribs/parser"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -53,4 +55,59 @@ func TestParseAttribsTag(t *testing.T) {
 		}
 	})
 
+	t.Run("test error position", func(t *testing.T) {
+		for _, ti := range []struct {
+			tag      string
+			position int
+			length   int
+		}{
+			{tag: "", position: 0, length: 0},
+			{tag: "required", position: 0, length: 8},
+			{tag: "name='hello-world'", position: 5, length: 13},
+			{tag: "name=hello, required=what", position: 21, length: 4},
+			{tag: "name=hello, pos='x'", position: 16, length: 3},
+			{tag: "name(x=1)", position: 0, length: 4},
+			{tag: "name=hello,", position: 10, length: 0},
+		} {
+			err := ValidateTag(ti.tag)
+			var pe parser.ParseError
+			if assert.ErrorAs(t, err, &pe, "tag: %q", ti.tag) {
+				assert.Equal(t, ti.position, pe.Position(), "tag: %q", ti.tag)
+				assert.Equal(t, ti.length, pe.Span().Length, "tag: %q", ti.tag)
+				assert.NotContains(t, pe.Message(), "[span", "tag: %q", ti.tag)
+			}
+		}
+
+		err := ValidateTag("name=hello, required=what")
+		assert.ErrorIs(t, err, ErrInvalidTag)
+		assert.Equal(t, "invalid tag: required not boolean", err.Error())
+		assert.NoError(t, ValidateTag("name=hello, unknown=1"))
+	})
+}
+
+func TestParseStructTag(t *testing.T) {
+	for _, item := range []struct {
+		tags     reflect.StructTag
+		key      string
+		expected TagOptions
+	}{
+		{tags: `attr:"name=path, pos=0, required, help='file path', default='/tmp', enum['/tmp', '/var']"`, key: "attr",
+			expected: TagOptions{Name: "path", Position: 0, Required: true, Help: "file path", Default: "'/tmp'", Enum: []string{"'/tmp'", "'/var'"}}},
+		{tags: `attr:"name=skip, disabled"`, key: "attr", expected: TagOptions{Name: "skip", Disabled: true, Position: -1}},
+		{tags: `json:"max_length,omitempty"`, key: "attr", expected: TagOptions{Name: "max_length", Position: -1}},
+		{tags: `json:"content-type"`, key: "attr", expected: TagOptions{Disabled: true, Position: -1}},
+		{tags: `json:"x" db:"name=column"`, key: "db", expected: TagOptions{Name: "column", Position: -1}},
+		{tags: ``, key: "attr", expected: TagOptions{Position: -1}},
+	} {
+		options, err := ParseStructTag(item.tags, item.key, "json")
+		assert.NoError(t, err, "tags: %s", item.tags)
+		assert.Equal(t, item.expected, options, "tags: %s", item.tags)
+	}
+
+	_, err := ParseStructTag(`attr:"required"`, "attr", "")
+	assert.EqualError(t, err, "attribute name is required")
+
+	options, err := ParseTag("name=id, pos=1")
+	assert.NoError(t, err)
+	assert.Equal(t, TagOptions{Name: "id", Position: 1}, options)
 }
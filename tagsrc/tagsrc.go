@@ -0,0 +1,204 @@
+// Package tagsrc maps positions in struct tag values back to Go source files.
+//
+// Attribute strings are usually stored in struct tags, so parser spans are relative to the tag value, not to the
+// file. Tag looks up value of tag key in struct tag literal (as written in source, with quotes and escapes) and
+// maps spans and parse errors back to token.Pos, so linters and editors can point at exact place in the file.
+package tagsrc
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/phonkee/attribs/parser"
+)
+
+var (
+	ErrInvalidTag = errors.New("invalid struct tag")
+)
+
+// Tag is value of single key in struct tag found in Go source
+type Tag struct {
+	// Key of the tag (e.g. attr)
+	Key string
+
+	// Value of the tag with quotes and escapes removed (same as reflect.StructTag.Get returns)
+	Value string
+
+	// position of tag literal in source
+	pos token.Pos
+
+	// offset in tag literal for each byte of Value, last item is offset of closing quote
+	offsets []int
+}
+
+// FromField looks up tag key in struct field. It returns nil tag when field has no tag or key is not present.
+func FromField(field *ast.Field, key string) (*Tag, error) {
+	if field == nil || field.Tag == nil {
+		return nil, nil
+	}
+	return FromLiteral(field.Tag.ValuePos, field.Tag.Value, key)
+}
+
+// FromLiteral looks up tag key in struct tag literal that starts at pos. Literal is written as in source including
+// quotes, e.g. `attr:"name=id"` or "attr:\"name=id\"". It returns nil tag when key is not present.
+func FromLiteral(pos token.Pos, literal string, key string) (*Tag, error) {
+	// struct tag itself is Go string literal (raw or interpreted)
+	tag, tagOffsets, err := unquote(literal)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTag, err)
+	}
+
+	// same rules as reflect.StructTag.Lookup
+	for i := 0; i < len(tag); {
+		// skip leading space
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		if i == len(tag) {
+			break
+		}
+
+		// scan to colon, space, quote or control character is not allowed in key
+		start := i
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == start || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			return nil, fmt.Errorf("%w: bad syntax at offset %d", ErrInvalidTag, i)
+		}
+		name := tag[start:i]
+		i++
+
+		// scan quoted string to find value
+		valueStart := i
+		i++
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			return nil, fmt.Errorf("%w: unterminated value of %s", ErrInvalidTag, name)
+		}
+		i++
+		if name != key {
+			continue
+		}
+
+		value, valueOffsets, err := unquote(tag[valueStart:i])
+		if err != nil {
+			return nil, fmt.Errorf("%w: value of %s: %v", ErrInvalidTag, name, err)
+		}
+
+		// compose both levels of quoting: value => tag => literal
+		offsets := make([]int, len(valueOffsets))
+		for j, offset := range valueOffsets {
+			offsets[j] = tagOffsets[valueStart+offset]
+		}
+
+		return &Tag{
+			Key:     key,
+			Value:   value,
+			pos:     pos,
+			offsets: offsets,
+		}, nil
+	}
+	return nil, nil
+}
+
+// Pos returns position in source of byte at given offset in Value, offset len(Value) is end of value.
+func (t *Tag) Pos(offset int) token.Pos {
+	offset = max(0, min(offset, len(t.Value)))
+	return t.pos + token.Pos(t.offsets[offset])
+}
+
+// SpanPos returns start and end (exclusive) positions in source of span produced by parsing Value.
+func (t *Tag) SpanPos(span *parser.SourceSpan) (start, end token.Pos) {
+	startOffset, endOffset := span.Start.Offset, span.End.Offset
+	// span without resolved locations has only rune position
+	if span.Start.Line == 0 {
+		startOffset = t.byteOffset(span.Position)
+		endOffset = t.byteOffset(span.Position + span.Length)
+	}
+	return t.Pos(startOffset), t.Pos(endOffset)
+}
+
+// ErrorPos returns start and end positions in source of parse error produced by parsing Value.
+// It returns false when err is not parser.ParseError.
+func (t *Tag) ErrorPos(err error) (start, end token.Pos, ok bool) {
+	var pe parser.ParseError
+	if !errors.As(err, &pe) {
+		return token.NoPos, token.NoPos, false
+	}
+	start, end = t.SpanPos(pe.Span())
+	return start, end, true
+}
+
+// ErrorPosition returns position in source file of parse error produced by parsing Value.
+// It returns false when err is not parser.ParseError.
+func (t *Tag) ErrorPosition(fset *token.FileSet, err error) (token.Position, bool) {
+	start, _, ok := t.ErrorPos(err)
+	if !ok {
+		return token.Position{}, false
+	}
+	return fset.Position(start), true
+}
+
+// byteOffset returns byte offset in Value of rune at given position.
+func (t *Tag) byteOffset(position int) int {
+	offset := 0
+	for i := 0; i < position && offset < len(t.Value); i++ {
+		_, size := utf8.DecodeRuneInString(t.Value[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// unquote unquotes Go string literal (interpreted or raw) and returns offset in literal for each byte of result,
+// last offset is offset of closing quote.
+func unquote(literal string) (string, []int, error) {
+	if len(literal) < 2 || literal[0] != literal[len(literal)-1] || (literal[0] != '"' && literal[0] != '`') {
+		return "", nil, fmt.Errorf("invalid string literal %s", literal)
+	}
+	quote := literal[0]
+	if quote == '"' && strings.Contains(literal, "\n") {
+		return "", nil, fmt.Errorf("newline in string literal %s", literal)
+	}
+	result := make([]byte, 0, len(literal)-2)
+	offsets := make([]int, 0, len(literal)-1)
+
+	for i := 1; i < len(literal)-1; {
+		// raw strings don't have escapes, carriage returns are discarded (same as Go compiler does)
+		if quote == '`' {
+			if literal[i] != '\r' {
+				result = append(result, literal[i])
+				offsets = append(offsets, i)
+			}
+			i++
+			continue
+		}
+
+		value, multibyte, tail, err := strconv.UnquoteChar(literal[i:len(literal)-1], quote)
+		if err != nil {
+			return "", nil, fmt.Errorf("invalid string literal %s: %w", literal, err)
+		}
+		size := len(result)
+		if value < utf8.RuneSelf || !multibyte {
+			result = append(result, byte(value))
+		} else {
+			result = utf8.AppendRune(result, value)
+		}
+		for range len(result) - size {
+			offsets = append(offsets, i)
+		}
+		i = len(literal) - 1 - len(tail)
+	}
+
+	return string(result), append(offsets, len(literal)-1), nil
+}
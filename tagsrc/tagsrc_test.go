@@ -0,0 +1,157 @@
+package tagsrc_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	attrparser "github.com/phonkee/attribs/parser"
+	"github.com/phonkee/attribs/tagsrc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const source = "package example\n" +
+	"\n" +
+	"type Example struct {\n" +
+	"\tRaw     string `json:\"raw\" attr:\"name=raw, span(start=@)\"`\n" +
+	"\tEscaped string \"attr:\\\"name=esc, s='\\\\\\\\q'\\\"\"\n" +
+	"\tUnicode string `attr:\"label='čšť', x=@\"`\n" +
+	"\tQuoted  string `attr:\"label=\\\"a\\\", x=@\"`\n" +
+	"\tHex     string \"attr:\\\"label='\\\\u010d\\\\x41', x=@\\\"\"\n" +
+	"\tNoAttr  string `json:\"no_attr\"`\n" +
+	"\tNoTag   string\n" +
+	"}\n"
+
+// fields parses source and returns struct fields by name.
+func fields(t *testing.T) (*token.FileSet, map[string]*ast.Field) {
+	t.Helper()
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "example.go", source, 0)
+	require.NoError(t, err)
+
+	result := map[string]*ast.Field{}
+	ast.Inspect(file, func(node ast.Node) bool {
+		if field, ok := node.(*ast.Field); ok && len(field.Names) > 0 {
+			result[field.Names[0].Name] = field
+		}
+		return true
+	})
+	return fset, result
+}
+
+func TestFromField(t *testing.T) {
+	fset, fields := fields(t)
+
+	for _, item := range []struct {
+		field string
+		value string
+		// text in source where error should point to
+		at string
+	}{
+		{field: "Raw", value: "name=raw, span(start=@)", at: "@)"},
+		{field: "Escaped", value: `name=esc, s='\q'`, at: `\\\\q`},
+		{field: "Unicode", value: "label='čšť', x=@", at: "@\""},
+		{field: "Quoted", value: `label="a", x=@`, at: "@\""},
+		{field: "Hex", value: "label='čA', x=@", at: "@\\\""},
+	} {
+		tag, err := tagsrc.FromField(fields[item.field], "attr")
+		require.NoError(t, err, "field: %s", item.field)
+		require.NotNil(t, tag, "field: %s", item.field)
+		assert.Equal(t, "attr", tag.Key)
+		assert.Equal(t, item.value, tag.Value, "field: %s", item.field)
+		literal, err := strconv.Unquote(fields[item.field].Tag.Value)
+		require.NoError(t, err)
+		assert.Equal(t, reflect.StructTag(literal).Get("attr"), tag.Value, "field: %s", item.field)
+
+		_, parseErr := attrparser.Parse(strings.NewReader(tag.Value), attrparser.Options{RejectStrayCharacters: true})
+		require.Error(t, parseErr, "field: %s", item.field)
+
+		position, ok := tag.ErrorPosition(fset, parseErr)
+		require.True(t, ok, "field: %s", item.field)
+		assert.Equal(t, "example.go", position.Filename)
+		assert.True(t, strings.HasPrefix(source[position.Offset:], item.at), "field: %s, points to: %q", item.field, source[position.Offset:])
+
+		// line and column are from the Go file
+		line := strings.Count(source[:position.Offset], "\n") + 1
+		assert.Equal(t, line, position.Line, "field: %s", item.field)
+		assert.Equal(t, position.Offset-strings.LastIndex(source[:position.Offset], "\n"), position.Column, "field: %s", item.field)
+	}
+
+	t.Run("missing", func(t *testing.T) {
+		for _, name := range []string{"NoAttr", "NoTag"} {
+			tag, err := tagsrc.FromField(fields[name], "attr")
+			assert.NoError(t, err)
+			assert.Nil(t, tag)
+		}
+	})
+
+	t.Run("not a parse error", func(t *testing.T) {
+		tag, err := tagsrc.FromField(fields["Raw"], "attr")
+		require.NoError(t, err)
+		_, ok := tag.ErrorPosition(fset, assert.AnError)
+		assert.False(t, ok)
+	})
+}
+
+func TestTagSpanPos(t *testing.T) {
+	fset, fields := fields(t)
+	tag, err := tagsrc.FromField(fields["Escaped"], "attr")
+	require.NoError(t, err)
+
+	root, err := attrparser.Parse(strings.NewReader("name=esc, s='x'"))
+	require.NoError(t, err)
+
+	// span of "esc" value, escapes before it don't matter, it's resolved and hand-made span
+	for _, span := range []*attrparser.SourceSpan{root.Object.Attributes[0].Value.Span, {Position: 5, Length: 3}} {
+		start, end := tag.SpanPos(span)
+		assert.Equal(t, "esc", source[fset.Position(start).Offset:fset.Position(end).Offset])
+	}
+
+	// escape sequence is underlined whole
+	start, end := tag.SpanPos(&attrparser.SourceSpan{Position: 13, Length: 1})
+	assert.Equal(t, `\\\\`, source[fset.Position(start).Offset:fset.Position(end).Offset])
+
+	// end of value is closing quote
+	assert.Equal(t, `\"`, source[fset.Position(tag.Pos(len(tag.Value))).Offset:][:2])
+}
+
+func TestFromLiteral(t *testing.T) {
+	for _, item := range []struct {
+		literal string
+		key     string
+		value   string
+		found   bool
+		err     bool
+	}{
+		{literal: "`attr:\"name=id\"`", key: "attr", value: "name=id", found: true},
+		{literal: "`json:\"id\"  attr:\"\"`", key: "attr", value: "", found: true},
+		{literal: "`attr:\"multi\nline\"`", key: "attr", err: true},
+		{literal: "`attr:\"x\r\"`", key: "attr", value: "x", found: true},
+		{literal: "`json:\"id\"`", key: "attr"},
+		{literal: "``", key: "attr"},
+		{literal: "`attr`", key: "attr", err: true},
+		{literal: "`attr:\"unterminated`", key: "attr", err: true},
+		{literal: "`attr:\"\\q\"`", key: "attr", err: true},
+		{literal: "\"attr:\\\"x\"", key: "attr", err: true},
+		{literal: "attr", key: "attr", err: true},
+	} {
+		tag, err := tagsrc.FromLiteral(token.NoPos, item.literal, item.key)
+		if item.err {
+			assert.ErrorIs(t, err, tagsrc.ErrInvalidTag, "literal: %q", item.literal)
+			continue
+		}
+		assert.NoError(t, err, "literal: %q", item.literal)
+		if !item.found {
+			assert.Nil(t, tag, "literal: %q", item.literal)
+			continue
+		}
+		if assert.NotNil(t, tag, "literal: %q", item.literal) {
+			assert.Equal(t, item.value, tag.Value, "literal: %q", item.literal)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+package attribs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// UsageFormat is output format of Usage
+type UsageFormat int
+
+const (
+	// UsageText is plain text with nested attributes indented
+	UsageText UsageFormat = iota
+	// UsageMarkdown is Markdown heading with nested list
+	UsageMarkdown
+	// UsageMan is man page (roff) in section 7
+	UsageMan
+)
+
+// UsageOptions configure Usage, zero value writes plain text titled by name of T
+type UsageOptions struct {
+	Format UsageFormat
+
+	// Title of reference (heading, man page name), empty means name of T
+	Title string
+}
+
+// usageEntry is documented attribute with its nested attributes
+type usageEntry struct {
+	name     string
+	attr     *attr
+	children []usageEntry
+	// recursive struct that is already documented by one of parents
+	recursive bool
+}
+
+// Usage returns human-readable reference of attributes: name, type, positional index, required marker, default,
+// allowed values (enum) and description (help) from attr tags. Attributes of nested structs (also in arrays and
+// maps) are nested under their parent, positional attributes come first and others are in declaration order.
+// Default and enum are documentation only, Parse and Bind neither apply defaults nor reject values outside of enum.
+func (d Definition[T]) Usage(options ...UsageOptions) string {
+	var opts UsageOptions
+	if len(options) > 0 {
+		opts = options[0]
+	}
+	if opts.Title == "" {
+		typ := reflect.TypeOf(*new(T))
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		opts.Title = typ.Name()
+		if opts.Title == "" {
+			opts.Title = "attributes"
+		}
+	}
+
+	entries := usageEntries(d.attr, map[*attr]bool{})
+
+	var sb strings.Builder
+	switch opts.Format {
+	case UsageMarkdown:
+		fmt.Fprintf(&sb, "# %s\n\n", opts.Title)
+		writeMarkdownUsage(&sb, entries, 0)
+	case UsageMan:
+		fmt.Fprintf(&sb, ".TH %s 7\n.SH NAME\n%s \\- attributes\n.SH ATTRIBUTES\n", manEscape(strings.ToUpper(opts.Title)), manEscape(opts.Title))
+		writeManUsage(&sb, entries)
+	default:
+		fmt.Fprintf(&sb, "Attributes of %s:\n\n", opts.Title)
+		writeTextUsage(&sb, entries, 2)
+	}
+	return sb.String()
+}
+
+// usageEntries returns documented attributes of struct attribute, positional first
+func usageEntries(a *attr, visiting map[*attr]bool) []usageEntry {
+	a = resolveStruct(a)
+	visiting[a] = true
+	defer delete(visiting, a)
+
+	var positional, named []usageEntry
+	for _, name := range a.Order {
+		prop := a.Properties[name]
+		if prop.Embedded {
+			continue
+		}
+		entry := usageEntry{name: name, attr: prop}
+		if nested := nestedStruct(prop); nested != nil {
+			if visiting[nested] {
+				entry.recursive = true
+			} else {
+				entry.children = usageEntries(nested, visiting)
+			}
+		}
+		if prop.IsPositional {
+			positional = append(positional, entry)
+		} else {
+			named = append(named, entry)
+		}
+	}
+	sort.SliceStable(positional, func(i, j int) bool {
+		return positional[i].attr.Position < positional[j].attr.Position
+	})
+	return append(positional, named...)
+}
+
+// resolveStruct returns struct attribute with properties, repeated and recursive structs point to it by Elem
+func resolveStruct(a *attr) *attr {
+	for a.Type == attrTypeStruct && a.Elem != nil {
+		a = a.Elem
+	}
+	return a
+}
+
+// nestedStruct returns struct of attribute, its array items or map values, nil when there is none
+func nestedStruct(a *attr) *attr {
+	for (a.Type == attrTypeArray || a.Type == attrTypeMap) && a.Elem != nil {
+		a = a.Elem
+	}
+	if a.Type != attrTypeStruct {
+		return nil
+	}
+	return resolveStruct(a)
+}
+
+// usageType returns type of attribute as users write it (integer, array of string)
+func usageType(a *attr) string {
+	switch a.Type {
+	case attrTypeStruct:
+		return "object"
+	case attrTypeArray:
+		return "array of " + usageType(a.Elem)
+	case attrTypeMap:
+		return "map of " + usageType(a.Elem)
+	case attrTypeBig:
+		return "number"
+	}
+	return string(a.Type)
+}
+
+// details returns type and tag documentation of entry, quote formats values
+func (e usageEntry) details(quote func(string) string) string {
+	parts := []string{usageType(e.attr)}
+	if e.attr.IsPositional {
+		parts = append(parts, fmt.Sprintf("position %d", e.attr.Position))
+	}
+	if e.attr.Required {
+		parts = append(parts, "required")
+	}
+	if e.attr.Default != "" {
+		parts = append(parts, "default: "+quote(e.attr.Default))
+	}
+	if len(e.attr.Enum) > 0 {
+		values := make([]string, 0, len(e.attr.Enum))
+		for _, value := range e.attr.Enum {
+			values = append(values, quote(value))
+		}
+		parts = append(parts, "one of: "+strings.Join(values, ", "))
+	}
+	if e.recursive {
+		parts = append(parts, "recursive")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func writeTextUsage(sb *strings.Builder, entries []usageEntry, indent int) {
+	prefix := strings.Repeat(" ", indent)
+	for _, entry := range entries {
+		fmt.Fprintf(sb, "%s%s (%s)\n", prefix, entry.name, entry.details(func(s string) string { return s }))
+		for _, line := range helpLines(entry.attr.Help) {
+			fmt.Fprintf(sb, "%s    %s\n", prefix, line)
+		}
+		writeTextUsage(sb, entry.children, indent+4)
+	}
+}
+
+func writeMarkdownUsage(sb *strings.Builder, entries []usageEntry, depth int) {
+	prefix := strings.Repeat("  ", depth)
+	for _, entry := range entries {
+		fmt.Fprintf(sb, "%s- %s (%s)", prefix, markdownCode(entry.name), entry.details(markdownCode))
+		if help := helpLines(entry.attr.Help); len(help) > 0 {
+			fmt.Fprintf(sb, " — %s", strings.Join(help, " "))
+		}
+		sb.WriteByte('\n')
+		writeMarkdownUsage(sb, entry.children, depth+1)
+	}
+}
+
+func writeManUsage(sb *strings.Builder, entries []usageEntry) {
+	for _, entry := range entries {
+		fmt.Fprintf(sb, ".TP\n.B %s\n(%s)\n", manEscape(entry.name), manEscape(entry.details(func(s string) string { return s })))
+		for _, line := range helpLines(entry.attr.Help) {
+			fmt.Fprintf(sb, "%s\n", manEscape(line))
+		}
+		if len(entry.children) > 0 {
+			sb.WriteString(".RS\n")
+			writeManUsage(sb, entry.children)
+			sb.WriteString(".RE\n")
+		}
+	}
+}
+
+// helpLines returns non-empty trimmed lines of help
+func helpLines(help string) []string {
+	var result []string
+	for _, line := range strings.Split(help, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			result = append(result, line)
+		}
+	}
+	return result
+}
+
+// markdownCode returns code span of s, it uses double backticks when s contains backtick
+func markdownCode(s string) string {
+	if strings.Contains(s, "`") {
+		return "`` " + s + " ``"
+	}
+	return "`" + s + "`"
+}
+
+// manEscape escapes backslashes and hyphens, so roff keeps them, and leading control characters of line
+func manEscape(s string) string {
+	s = strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
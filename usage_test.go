@@ -0,0 +1,126 @@
+package attribs_test
+
+import (
+	"testing"
+
+	"github.com/phonkee/attribs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUsage(t *testing.T) {
+	type Span struct {
+		Start int `attr:"name=start, help='first rune'"`
+		End   int `attr:"name=end"`
+	}
+	type Base struct {
+		Debug bool `attr:"name=debug, help='enable debug-level logs'"`
+	}
+	type Node struct {
+		Name     string  `attr:"name=name"`
+		Children []*Node `attr:"name=children"`
+	}
+	type Plugin struct {
+		Base
+		Mode   string            `attr:"name=mode, required, default=fast, enum[fast, 'safe mode'], help='Execution mode.\\nSafe mode is slower.'"`
+		Path   string            `attr:"name=path, pos=0, required, help='path to plugin'"`
+		Spans  []Span            `attr:"name=spans"`
+		Labels map[string]string `attr:"name=labels, default(a=x)"`
+		Tree   Node              `attr:"name=tree"`
+	}
+
+	d := attribs.Must(attribs.New(Plugin{}))
+
+	t.Run("test text", func(t *testing.T) {
+		assert.Equal(t, `Attributes of Plugin:
+
+  path (string, position 0, required)
+      path to plugin
+  debug (boolean)
+      enable debug-level logs
+  mode (string, required, default: 'fast', one of: 'fast', 'safe mode')
+      Execution mode.
+      Safe mode is slower.
+  spans (array of object)
+      start (integer)
+          first rune
+      end (integer)
+  labels (map of string, default: (a=x))
+  tree (object)
+      name (string)
+      children (array of object, recursive)
+`, d.Usage())
+	})
+
+	t.Run("test markdown", func(t *testing.T) {
+		assert.Equal(t, "# Options\n\n"+
+			"- `path` (string, position 0, required) — path to plugin\n"+
+			"- `debug` (boolean) — enable debug-level logs\n"+
+			"- `mode` (string, required, default: `'fast'`, one of: `'fast'`, `'safe mode'`) — Execution mode. Safe mode is slower.\n"+
+			"- `spans` (array of object)\n"+
+			"  - `start` (integer) — first rune\n"+
+			"  - `end` (integer)\n"+
+			"- `labels` (map of string, default: `(a=x)`)\n"+
+			"- `tree` (object)\n"+
+			"  - `name` (string)\n"+
+			"  - `children` (array of object, recursive)\n",
+			d.Usage(attribs.UsageOptions{Format: attribs.UsageMarkdown, Title: "Options"}))
+	})
+
+	t.Run("test man", func(t *testing.T) {
+		assert.Equal(t, `.TH PLUGIN 7
+.SH NAME
+Plugin \- attributes
+.SH ATTRIBUTES
+.TP
+.B path
+(string, position 0, required)
+path to plugin
+.TP
+.B debug
+(boolean)
+enable debug\-level logs
+.TP
+.B mode
+(string, required, default: 'fast', one of: 'fast', 'safe mode')
+Execution mode.
+Safe mode is slower.
+.TP
+.B spans
+(array of object)
+.RS
+.TP
+.B start
+(integer)
+first rune
+.TP
+.B end
+(integer)
+.RE
+.TP
+.B labels
+(map of string, default: (a=x))
+.TP
+.B tree
+(object)
+.RS
+.TP
+.B name
+(string)
+.TP
+.B children
+(array of object, recursive)
+.RE
+`, d.Usage(attribs.UsageOptions{Format: attribs.UsageMan}))
+	})
+
+	t.Run("test pointer", func(t *testing.T) {
+		assert.Equal(t, "Attributes of Span:\n\n  start (integer)\n      first rune\n  end (integer)\n",
+			attribs.Must(attribs.New(&Span{})).Usage())
+	})
+
+	t.Run("test invalid tags", func(t *testing.T) {
+		for _, tag := range []string{"name=a, help[x]", "name=a, help=1", "name=a, enum=x", "name=a, enum[(x=1)]"} {
+			assert.Error(t, attribs.ValidateTag(tag), "tag: %q", tag)
+		}
+	})
+}